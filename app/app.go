@@ -30,6 +30,7 @@ import (
 	"github.com/spf13/viper"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/abci/version"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/libs/cli"
 	"github.com/tendermint/tendermint/libs/log"
 	tmos "github.com/tendermint/tendermint/libs/os"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/cosmwasm/wasmd/x/wasm"
 
+	"github.com/cybercongress/go-cyber/merkle"
 	"github.com/cybercongress/go-cyber/store"
 	"github.com/cybercongress/go-cyber/types"
 	"github.com/cybercongress/go-cyber/types/coin"
@@ -127,13 +129,15 @@ type CyberdApp struct {
 	wasmKeeper     		   wasm.Keeper
 
 	latestBlockHeight int64
+	pendingTxs        *pendingTxs
 
 	mm *module.Manager
 }
 
 func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest bool,
 	invCheckPeriod uint, skipUpgradeHeights map[int64]bool,
-	computeUnit rank.ComputeUnit, allowSearch bool,
+	computeUnit rank.ComputeUnit, allowSearch bool, personalizedRankConfig rank.PersonalizedRankConfig,
+	rankProofsDir string,
 	baseAppOptions ...func(*baseapp.BaseApp),
 ) *CyberdApp {
 
@@ -153,6 +157,7 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 		dbKeys:         dbKeys,
 		mainKeeper:     mainKeeper,
 		subspaces:      make(map[string]params.Subspace),
+		pendingTxs:     newPendingTxs(),
 	}
 
 	app.paramsKeeper = params.NewKeeper(app.cdc, dbKeys.params, dbKeys.tParams)
@@ -201,6 +206,31 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 	app.upgradeKeeper = upgrade.NewKeeper(skipUpgradeHeights, dbKeys.upgrade, app.cdc)
 
 	app.upgradeKeeper.SetUpgradeHandler("darwin", func(ctx sdk.Context, plan upgrade.Plan) {})
+	// rank trees of calculations started after upgrade height are hashed with leaf/node prefixes
+	app.upgradeKeeper.SetUpgradeHandler(rank.TreeDomainSeparationUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.rankStateKeeper.SetTreeVersion(ctx, merkle.DomainSeparatedVersion)
+	})
+	// spent bandwidth values of blocks out of recovery window are deleted after upgrade height
+	app.upgradeKeeper.SetUpgradeHandler(bandwidth.BlockSpentPruningUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.blockBandwidthKeeper.EnablePruning(ctx)
+	})
+	// accounts bandwidth is re-encoded from JSON to versioned amino encoding at upgrade height
+	app.upgradeKeeper.SetUpgradeHandler(bandwidth.AccountBandwidthEncodingUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		if err := app.accountBandwidthKeeper.MigrateAccountsBandwidthEncoding(ctx); err != nil {
+			panic(err)
+		}
+	})
+	// stake bandwidth, accounts recovery, priced costs and price are calculated with fixed point arithmetic
+	// from upgrade height
+	app.upgradeKeeper.SetUpgradeHandler(bandwidth.FixedPointMathUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		if err := app.bandwidthMeter.EnableFixedPointMath(ctx); err != nil {
+			panic(err)
+		}
+	})
+	// bandwidth payer designated in tx memo is respected and karma accrues to linking neurons from upgrade height
+	app.upgradeKeeper.SetUpgradeHandler(bandwidth.SponsoredBandwidthUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.accountBandwidthKeeper.EnableSponsoredBandwidth(ctx)
+	})
 
 	var wasmRouter = baseApp.Router()
 	homeDir := viper.GetString(cli.HomeFlag)
@@ -215,7 +245,9 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 	app.evidenceKeeper = *evidenceKeeper
 
 
-	app.accountBandwidthKeeper = bandwidth.NewAccountBandwidthKeeper(app.cdc, dbKeys.accBandwidth, app.subspaces[bandwidth.ModuleName])
+	app.accountBandwidthKeeper = bandwidth.NewAccountBandwidthKeeper(
+		app.cdc, dbKeys.accBandwidth, dbKeys.bandwidth, app.subspaces[bandwidth.ModuleName],
+	)
 	app.blockBandwidthKeeper = bandwidth.NewBlockSpentBandwidthKeeper(dbKeys.blockBandwidth)
 
 	// register the proposal types
@@ -234,7 +266,7 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 	app.stakingIndexKeeper = cyberbank.NewIndexedKeeper(bankKeeper)
 	app.rankStateKeeper = rank.NewStateKeeper(app.cdc, app.subspaces[rank.ModuleName],
 		allowSearch, app.mainKeeper, app.stakingIndexKeeper,
-		app.linkIndexedKeeper, app.cidNumKeeper, computeUnit,
+		app.linkIndexedKeeper, app.cidNumKeeper, computeUnit, personalizedRankConfig, rankProofsDir,
 	)
 
 	app.stakingKeeper = *stakingKeeper.SetHooks(
@@ -263,7 +295,7 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 		staking.NewAppModule(app.stakingKeeper, app.accountKeeper, app.supplyKeeper),
 		upgrade.NewAppModule(app.upgradeKeeper),
 		evidence.NewAppModule(app.evidenceKeeper),
-		bandwidth.NewAppModule(app.accountBandwidthKeeper, app.blockBandwidthKeeper),
+		bandwidth.NewAppModule(app.accountBandwidthKeeper, app.blockBandwidthKeeper, app.bandwidthMeter),
 		link.NewAppModule(app.cidNumKeeper, app.linkIndexedKeeper, app.accountKeeper, app.accountBandwidthKeeper, app.bandwidthMeter),
 		rank.NewAppModule(app.rankStateKeeper),
 		wasm.NewAppModule(app.wasmKeeper),
@@ -275,7 +307,7 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 	app.MountStores(
 		dbKeys.main, dbKeys.auth, dbKeys.cidNum, dbKeys.cidNumReverse, dbKeys.links,
 		dbKeys.rank, dbKeys.stake, dbKeys.slashing, dbKeys.gov, dbKeys.params,
-		dbKeys.distr, dbKeys.accBandwidth, dbKeys.blockBandwidth, dbKeys.tParams,
+		dbKeys.distr, dbKeys.accBandwidth, dbKeys.blockBandwidth, dbKeys.bandwidth, dbKeys.tParams,
 		dbKeys.tStake, dbKeys.mint, dbKeys.supply, dbKeys.upgrade, dbKeys.evidence, dbKeys.wasm,
 	)
 
@@ -307,11 +339,9 @@ func NewCyberdApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest
 	// build context for current rank calculation round
 	calculationPeriod := app.rankStateKeeper.GetParams(ctx).CalculationPeriod
 
-	rankRoundBlockNumber := (app.latestBlockHeight / calculationPeriod) * calculationPeriod
-	if rankRoundBlockNumber == 0 && app.latestBlockHeight >= 1 {
-		rankRoundBlockNumber = 1 // special case cause tendermint blocks start from 1
-	}
-	rankCtx, err := util.NewContextWithMSVersion(db, rankRoundBlockNumber, dbKeys.GetStoreKeys()...)
+	rankCtx, err := util.NewContextWithMSVersion(
+		db, rankRoundBlockNumber(app.latestBlockHeight, calculationPeriod), dbKeys.GetStoreKeys()...,
+	)
 	if err != nil {
 		tmos.Exit(err.Error())
 	}
@@ -362,8 +392,8 @@ func (app *CyberdApp) applyGenesis(ctx sdk.Context, req abci.RequestInitChain) a
 	gov.InitGenesis(ctx, app.govKeeper, app.supplyKeeper, genesisState.GovData)
 	mint.InitGenesis(ctx, app.mintKeeper, genesisState.MintData)
 	supply.InitGenesis(ctx, app.supplyKeeper, app.accountKeeper, genesisState.SupplyData)
-	bandwidth.InitAccountsBandwidthGenesis(ctx, app.bandwidthMeter, app.accountBandwidthKeeper, genesisState.GetAddresses(),
-		genesisState.BandwidthData)
+	bandwidth.InitAccountsBandwidthGenesis(ctx, app.bandwidthMeter, app.accountBandwidthKeeper, app.blockBandwidthKeeper,
+		genesisState.GetAddresses(), genesisState.BandwidthData)
 	rank.InitGenesis(ctx, app.rankStateKeeper, genesisState.RankData)
 
 	err = link.InitGenesis(ctx, app.cidNumKeeper, app.linkIndexedKeeper, app.Logger())
@@ -429,19 +459,32 @@ func (app *CyberdApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx
 
 	if err == nil {
 		txCost := app.bandwidthMeter.GetPricedTxCost(ctx, tx)
-		accBw := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, acc)
+		accBw, bwErr := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, acc)
+		if bwErr != nil {
+			return sdkerrors.ResponseCheckTx(bwErr, 0, 0)
+		}
 
 		curBlockSpentBandwidth := app.bandwidthMeter.GetCurBlockSpentBandwidth(ctx)
 		maxBlockBandwidth := app.bandwidthMeter.GetMaxBlockBandwidth(ctx)
 
 		if !accBw.HasEnoughRemained(txCost) {
 			err = bandwidth.ErrNotEnoughBandwidth
-		} else if (uint64(txCost) + curBlockSpentBandwidth) > maxBlockBandwidth  {
+		} else if (uint64(txCost) + curBlockSpentBandwidth) > maxBlockBandwidth {
 			err = bandwidth.ErrExceededMaxBlockBandwidth
 		} else {
 			resp := app.BaseApp.CheckTx(req)
 			if resp.Code == 0 {
-				app.bandwidthMeter.ConsumeAccBandwidth(ctx, accBw, txCost)
+				if err = app.bandwidthMeter.ConsumeAccBandwidth(ctx, accBw, txCost); err != nil {
+					return sdkerrors.ResponseCheckTx(err, uint64(resp.GasWanted), uint64(resp.GasUsed))
+				}
+				// bandwidth reserved by pending tx is released by check state reset on commit,
+				// it's reserved again only if tx passes recheck
+				app.pendingTxs.add(string(tmhash.Sum(req.GetTx())), acc, txCost)
+				pendingCount, reserved := app.pendingTxs.get(acc)
+				// tendermint mempool doesn't order txs yet, priority is reported for clients and peers
+				resp.Events = append(resp.Events, abci.Event(bandwidth.NewMempoolBandwidthEvent(
+					acc, txCost, accBw.TxPriority(txCost), pendingCount, reserved,
+				)))
 			}
 			return resp
 		}
@@ -453,41 +496,68 @@ func (app *CyberdApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx
 func (app *CyberdApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliverTx) {
 
 	ctx := app.NewContext(false, abci.Header{Height: app.latestBlockHeight})
+	// tx included to block leaves mempool, its bandwidth is consumed in deliver state
+	app.pendingTxs.release(string(tmhash.Sum(req.GetTx())))
 	tx, acc, err := app.decodeTxAndAccount(ctx, req.GetTx())
 
 	if err != nil {
 		return sdkerrors.ResponseDeliverTx(err, 0, 0)
 	}
 
-	if err == nil {
-
-			txCost := app.bandwidthMeter.GetPricedTxCost(ctx, tx)
-		accBw := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, acc)
-
-		curBlockSpentBandwidth := app.bandwidthMeter.GetCurBlockSpentBandwidth(ctx)
-		maxBlockBandwidth := app.bandwidthMeter.GetMaxBlockBandwidth(ctx)
-
-		if !accBw.HasEnoughRemained(txCost) {
-			err = bandwidth.ErrNotEnoughBandwidth
-		} else if (uint64(txCost) + curBlockSpentBandwidth) > maxBlockBandwidth  {
-			err = bandwidth.ErrExceededMaxBlockBandwidth
-		} else {
-			resp := app.BaseApp.DeliverTx(req)
-			app.bandwidthMeter.ConsumeAccBandwidth(ctx, accBw, txCost)
+	txCost := app.bandwidthMeter.GetPricedTxCost(ctx, tx)
+	accBw, err := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, acc)
+	if err != nil {
+		return sdkerrors.ResponseDeliverTx(err, 0, 0)
+	}
+	// karma receivers records are validated prior delivery too, tx effects can't be reverted after it
+	linkingCost := app.bandwidthMeter.GetPricedLinksCost(ctx, tx)
+	karmaShares := app.karmaShares(ctx, tx, acc, linkingCost)
+	for _, share := range karmaShares {
+		if _, err := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, share.Neuron); err != nil {
+			return sdkerrors.ResponseDeliverTx(err, 0, 0)
+		}
+	}
 
-			if resp.Code == 0 {
-				linkingCost := app.bandwidthMeter.GetPricedLinksCost(ctx, tx)
-				if linkingCost != int64(0) {
-					accBwNew := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, acc)
-					app.bandwidthMeter.UpdateLinkedBandwidth(ctx, accBwNew, linkingCost)
+	curBlockSpentBandwidth := app.bandwidthMeter.GetCurBlockSpentBandwidth(ctx)
+	maxBlockBandwidth := app.bandwidthMeter.GetMaxBlockBandwidth(ctx)
+
+	if !accBw.HasEnoughRemained(txCost) {
+		err = bandwidth.ErrNotEnoughBandwidth
+	} else if (uint64(txCost) + curBlockSpentBandwidth) > maxBlockBandwidth {
+		err = bandwidth.ErrExceededMaxBlockBandwidth
+	} else {
+		app.linkIndexedKeeper.BeginTx()
+		resp := app.BaseApp.DeliverTx(req)
+		if resp.Code != 0 {
+			app.linkIndexedKeeper.DiscardTxLinks()
+		}
+		// records were loaded before delivery, so failing here means bandwidth store is corrupted
+		if err = app.bandwidthMeter.ConsumeAccBandwidth(ctx, accBw, txCost); err != nil {
+			panic(err)
+		}
+		rawTxCost := app.bandwidthMeter.GetTxCost(ctx, tx)
+		ctx.EventManager().EmitEvent(
+			bandwidth.NewBandwidthEvent(acc, rawTxCost, txCost, app.bandwidthMeter.GetCurrentCreditPrice()),
+		)
+
+		if resp.Code == 0 {
+			for _, share := range karmaShares {
+				accBwNew, err := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, share.Neuron)
+				if err != nil {
+					panic(err)
+				}
+				if err = app.bandwidthMeter.UpdateLinkedBandwidth(ctx, accBwNew, share.Amount); err != nil {
+					panic(err)
 				}
-				app.bandwidthMeter.AddToBlockKarma(linkingCost)
 			}
+			app.bandwidthMeter.AddToBlockKarma(linkingCost)
+		}
 
-			app.bandwidthMeter.AddToBlockBandwidth(app.bandwidthMeter.GetTxCost(ctx, tx))
+		app.bandwidthMeter.AddToBlockBandwidth(rawTxCost)
 
-			return resp
-		}
+		// bandwidth and karma events are indexed with tx events
+		resp.Events = append(resp.Events, ctx.EventManager().ABCIEvents()...)
+		return resp
 	}
 
 	return sdkerrors.ResponseDeliverTx(err, 0, 0)
@@ -509,8 +579,11 @@ func (app *CyberdApp) decodeTxAndAccount(ctx sdk.Context, txBytes []byte) (auth.
 		return tx, nil, err
 	}
 
-	// signers acc [0] bandwidth will be consumed
-	account := tx.GetSigners()[0]
+	// signers acc [0] or designated payer bandwidth will be consumed
+	account, err := app.bandwidthPayer(ctx, tx)
+	if err != nil {
+		return tx, nil, err
+	}
 	acc := app.accountKeeper.GetAccount(ctx, account)
 	if acc == nil {
 		return tx, nil, sdkerrors.ErrUnknownAddress
@@ -560,6 +633,7 @@ func (app *CyberdApp) EndBlocker(ctx sdk.Context, _ abci.RequestEndBlock) abci.R
 // Implements ABCI
 func (app *CyberdApp) Commit() (res abci.ResponseCommit) {
 	app.BaseApp.Commit()
+	app.pendingTxs.reset()
 	return abci.ResponseCommit{Data: app.appHash()}
 }
 
@@ -0,0 +1,89 @@
+package app
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cybercongress/go-cyber/x/bandwidth"
+)
+
+// Bandwidth tx would consume if checked now. Tx is not executed, so it could be unsigned.
+type BandwidthEstimation struct {
+	TxCost          int64 `json:"tx_cost"`
+	PricedTxCost    int64 `json:"priced_tx_cost"`
+	PricedLinksCost int64 `json:"priced_links_cost"`
+
+	// signer which bandwidth would be consumed, first one or designated payer
+	Address           sdk.AccAddress `json:"address"`
+	RemainedBandwidth int64          `json:"remained_bandwidth"`
+	EnoughBandwidth   bool           `json:"enough_bandwidth"`
+
+	BlockSpentBandwidth uint64 `json:"block_spent_bandwidth"`
+	MaxBlockBandwidth   uint64 `json:"max_block_bandwidth"`
+	FitsBlock           bool   `json:"fits_block"`
+}
+
+// Estimates bandwidth of encoded tx with the same checks as CheckTx does.
+func (app *CyberdApp) SimulateBandwidth(txBytes []byte) (BandwidthEstimation, error) {
+
+	ctx := app.RpcContext()
+
+	decoded, err := app.txDecoder(txBytes)
+	if err != nil {
+		return BandwidthEstimation{}, err
+	}
+
+	tx, ok := decoded.(auth.StdTx)
+	if !ok || len(tx.GetMsgs()) == 0 {
+		return BandwidthEstimation{}, sdkerrors.ErrInvalidRequest
+	}
+
+	// signers acc [0] or designated payer bandwidth will be consumed
+	account, err := app.bandwidthPayer(ctx, tx)
+	if err != nil {
+		return BandwidthEstimation{}, err
+	}
+	if app.accountKeeper.GetAccount(ctx, account) == nil {
+		return BandwidthEstimation{}, sdkerrors.ErrUnknownAddress
+	}
+
+	pricedTxCost := app.bandwidthMeter.GetPricedTxCost(ctx, tx)
+	accBw, err := app.bandwidthMeter.GetCurrentAccBandwidth(ctx, account)
+	if err != nil {
+		return BandwidthEstimation{}, err
+	}
+	curBlockSpentBandwidth := app.bandwidthMeter.GetCurBlockSpentBandwidth(ctx)
+	maxBlockBandwidth := app.bandwidthMeter.GetMaxBlockBandwidth(ctx)
+
+	return BandwidthEstimation{
+		TxCost:              app.bandwidthMeter.GetTxCost(ctx, tx),
+		PricedTxCost:        pricedTxCost,
+		PricedLinksCost:     app.bandwidthMeter.GetPricedLinksCost(ctx, tx),
+		Address:             account,
+		RemainedBandwidth:   accBw.RemainedValue,
+		EnoughBandwidth:     accBw.HasEnoughRemained(pricedTxCost),
+		BlockSpentBandwidth: curBlockSpentBandwidth,
+		MaxBlockBandwidth:   maxBlockBandwidth,
+		FitsBlock:           uint64(pricedTxCost)+curBlockSpentBandwidth <= maxBlockBandwidth,
+	}, nil
+}
+
+// Payer designated in memo is respected after sponsored bandwidth upgrade only, signers acc [0] pays before it.
+func (app *CyberdApp) bandwidthPayer(ctx sdk.Context, tx auth.StdTx) (sdk.AccAddress, error) {
+	if !app.accountBandwidthKeeper.IsSponsoredBandwidthEnabled(ctx) {
+		return tx.GetSigners()[0], nil
+	}
+	return bandwidth.GetBandwidthPayer(tx)
+}
+
+// Karma accrues to bandwidth payer before sponsored bandwidth upgrade, and to linking neurons of tx after it.
+func (app *CyberdApp) karmaShares(ctx sdk.Context, tx auth.StdTx, payer sdk.AccAddress, linkingCost int64) []bandwidth.KarmaShare {
+	if linkingCost == 0 {
+		return nil
+	}
+	if !app.accountBandwidthKeeper.IsSponsoredBandwidthEnabled(ctx) {
+		return []bandwidth.KarmaShare{{Neuron: payer, Amount: linkingCost}}
+	}
+	return bandwidth.SplitLinkingCost(tx, linkingCost)
+}
@@ -170,6 +170,8 @@ func NewDefaultGenesisState() GenesisState {
 		},
 		BandwidthData: bandwidth.GenesisState{
 			Params:		bandwidth.DefaultParams(),
+			// new networks start with bandwidth upgrades enabled
+			Upgrades:	bandwidth.GenesisUpgrades,
 		},
 		RankData: rank.GenesisState{
 			Params: 	rank.DefaultParams(),
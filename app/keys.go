@@ -14,6 +14,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/upgrade"
 	"github.com/cosmos/cosmos-sdk/x/evidence"
 	"github.com/cosmwasm/wasmd/x/wasm"
+
+	"github.com/cybercongress/go-cyber/x/bandwidth"
+	"github.com/cybercongress/go-cyber/x/link"
 )
 
 type cyberdAppDbKeys struct {
@@ -35,6 +38,7 @@ type cyberdAppDbKeys struct {
 	rank           *sdk.KVStoreKey
 	accBandwidth   *sdk.KVStoreKey
 	blockBandwidth *sdk.KVStoreKey
+	bandwidth      *sdk.KVStoreKey
 
 	wasm 		   *sdk.KVStoreKey
 
@@ -56,12 +60,13 @@ func NewCyberdAppDbKeys() cyberdAppDbKeys {
 		upgrade:  sdk.NewKVStoreKey(upgrade.StoreKey),
 		evidence: sdk.NewKVStoreKey(evidence.StoreKey),
 
-		cidNum:         sdk.NewKVStoreKey("cid_index"), // TODO
-		cidNumReverse:  sdk.NewKVStoreKey("cid_index_reverse"),
+		cidNum:         sdk.NewKVStoreKey(link.CidStoreKey), // TODO
+		cidNumReverse:  sdk.NewKVStoreKey(link.CidReverseStoreKey),
 		links:          sdk.NewKVStoreKey("cyberlinks"),
 		rank:           sdk.NewKVStoreKey("rank"),
 		accBandwidth:   sdk.NewKVStoreKey("acc_bandwidth"),
 		blockBandwidth: sdk.NewKVStoreKey("block_spent_bandwidth"),
+		bandwidth:      sdk.NewKVStoreKey(bandwidth.StoreKey),
 
 		wasm:			sdk.NewKVStoreKey(wasm.StoreKey),
 
@@ -73,7 +78,7 @@ func NewCyberdAppDbKeys() cyberdAppDbKeys {
 func (k cyberdAppDbKeys) GetStoreKeys() []*sdk.KVStoreKey {
 	return []*sdk.KVStoreKey{
 		k.main, k.auth, k.cidNum, k.cidNumReverse, k.links, k.rank, k.stake, k.supply, k.gov,
-		k.slashing, k.params, k.distr, k.accBandwidth, k.blockBandwidth, k.mint, k.upgrade, k.evidence, k.wasm,
+		k.slashing, k.params, k.distr, k.accBandwidth, k.blockBandwidth, k.bandwidth, k.mint, k.upgrade, k.evidence, k.wasm,
 	}
 }
 
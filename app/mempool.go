@@ -0,0 +1,88 @@
+package app
+
+import (
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type pendingTx struct {
+	account string
+	cost    int64
+}
+
+// Keeps txs accepted to mempool, which bandwidth is reserved in check state, per account.
+// Check state is reset to committed one on commit, so all reservations are released then and made again
+// only for txs passed recheck. Txs passed check but not added to mempool are dropped on commit too.
+type pendingTxs struct {
+	mtx sync.Mutex
+
+	txs      map[string]pendingTx
+	accounts map[string]accountPendingTxs
+}
+
+type accountPendingTxs struct {
+	count    int
+	reserved int64
+}
+
+func newPendingTxs() *pendingTxs {
+	return &pendingTxs{
+		txs:      make(map[string]pendingTx),
+		accounts: make(map[string]accountPendingTxs),
+	}
+}
+
+func (p *pendingTxs) add(txHash string, account sdk.AccAddress, cost int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.remove(txHash)
+	tx := pendingTx{account: account.String(), cost: cost}
+	p.txs[txHash] = tx
+
+	accTxs := p.accounts[tx.account]
+	accTxs.count++
+	accTxs.reserved += cost
+	p.accounts[tx.account] = accTxs
+}
+
+// Releases all reservations, called on commit as check state is reset
+func (p *pendingTxs) reset() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.txs = make(map[string]pendingTx)
+	p.accounts = make(map[string]accountPendingTxs)
+}
+
+func (p *pendingTxs) release(txHash string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.remove(txHash)
+}
+
+func (p *pendingTxs) remove(txHash string) {
+	tx, ok := p.txs[txHash]
+	if !ok {
+		return
+	}
+	delete(p.txs, txHash)
+
+	accTxs := p.accounts[tx.account]
+	accTxs.count--
+	accTxs.reserved -= tx.cost
+	if accTxs.count == 0 {
+		delete(p.accounts, tx.account)
+		return
+	}
+	p.accounts[tx.account] = accTxs
+}
+
+// Returns pending txs count and bandwidth reserved by them for account
+func (p *pendingTxs) get(account sdk.AccAddress) (int, int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	accTxs := p.accounts[account.String()]
+	return accTxs.count, accTxs.reserved
+}
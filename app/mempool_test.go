@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingTxs(t *testing.T) {
+	pending := newPendingTxs()
+	acc1 := sdk.AccAddress([]byte("acc1"))
+	acc2 := sdk.AccAddress([]byte("acc2"))
+
+	pending.add("tx1", acc1, 100)
+	pending.add("tx2", acc1, 200)
+	pending.add("tx3", acc2, 50)
+	count, reserved := pending.get(acc1)
+	require.Equal(t, 2, count)
+	require.Equal(t, int64(300), reserved)
+
+	// passed recheck with new price
+	pending.release("tx1")
+	pending.add("tx1", acc1, 150)
+	count, reserved = pending.get(acc1)
+	require.Equal(t, 2, count)
+	require.Equal(t, int64(350), reserved)
+
+	// delivered and failed recheck
+	pending.release("tx1")
+	pending.release("tx2")
+	pending.release("tx2")
+	count, reserved = pending.get(acc1)
+	require.Equal(t, 0, count)
+	require.Equal(t, int64(0), reserved)
+	require.NotContains(t, pending.accounts, acc1.String())
+
+	count, reserved = pending.get(acc2)
+	require.Equal(t, 1, count)
+	require.Equal(t, int64(50), reserved)
+}
+
+func TestPendingTxsReset(t *testing.T) {
+	pending := newPendingTxs()
+	acc := sdk.AccAddress([]byte("acc"))
+
+	// tx passed check, but wasn't added to mempool, so it's neither delivered nor rechecked
+	pending.add("tx1", acc, 100)
+	pending.add("tx2", acc, 200)
+	pending.reset()
+	count, reserved := pending.get(acc)
+	require.Equal(t, 0, count)
+	require.Equal(t, int64(0), reserved)
+	require.Empty(t, pending.txs)
+
+	// passed recheck after commit
+	pending.add("tx2", acc, 200)
+	count, reserved = pending.get(acc)
+	require.Equal(t, 1, count)
+	require.Equal(t, int64(200), reserved)
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/exported"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cybercongress/go-cyber/merkle"
+	cbd "github.com/cybercongress/go-cyber/types"
+	"github.com/cybercongress/go-cyber/util"
+	"github.com/cybercongress/go-cyber/x/link"
+	"github.com/cybercongress/go-cyber/x/rank"
+)
+
+// Offline rank tooling. Rank values are not persisted (only merkle subtrees roots are),
+// so they are recalculated on CPU from links and stakes committed at the rank round height.
+// No consensus state is modified.
+
+type RankSnapshotEntry struct {
+	Cid       link.Cid
+	Number    link.CidNumber
+	Rank      float64
+	InDegree  int
+	OutDegree int
+}
+
+type RankSnapshot struct {
+	Height  int64 // rank round height links and stakes were taken from
+	Hash    []byte
+	Entries []RankSnapshotEntry
+}
+
+type RankSimulationResult struct {
+	DampingFactor float64
+	Tolerance     float64
+	Hash          []byte
+	Iterations    int
+	Top           []RankedCid
+}
+
+type RankSimulation struct {
+	Height    int64 // rank round height links and stakes were taken from
+	Live      RankSimulationResult
+	Simulated RankSimulationResult
+}
+
+// stakes collected from committed state, never change
+type snapshotStakes map[cbd.AccNumber]uint64
+
+func (s snapshotStakes) FixUserStake(_ sdk.Context) bool          { return false }
+func (s snapshotStakes) GetTotalStakes() map[cbd.AccNumber]uint64 { return s }
+
+// Recalculates current network rank and checks it against network rank hash.
+func (app *CyberdApp) ExportNetworkRank(db dbm.DB) (RankSnapshot, error) {
+
+	ctx := app.RpcContext()
+	// links stakes are decayed by height calculation was started at
+	height := app.mainKeeper.GetLatestRankCalcHeight(ctx)
+	if height == 0 {
+		var err error
+		if height, err = app.networkRankRoundHeight(); err != nil {
+			return RankSnapshot{}, err
+		}
+	}
+
+	rankCtx, err := util.NewContextWithMSVersion(db, height, app.dbKeys.GetStoreKeys()...)
+	if err != nil {
+		return RankSnapshot{}, err
+	}
+
+	params := app.rankStateKeeper.GetParams(rankCtx)
+	dampingFactor, err := strconv.ParseFloat(params.DampingFactor.String(), 64)
+	if err != nil {
+		return RankSnapshot{}, err
+	}
+	tolerance, err := strconv.ParseFloat(params.Tolerance.String(), 64)
+	if err != nil {
+		return RankSnapshot{}, err
+	}
+
+	treeVersion := app.mainKeeper.GetLatestMerkleTreeVersion(ctx)
+
+	calcCtx := app.loadRankCalcContext(rankCtx, dampingFactor, tolerance, params.LinkWeightHalfLife, treeVersion)
+	networkRank := rank.CalculateRank(calcCtx, rank.CPU, app.Logger())
+
+	// network rank is extended with zero values for cids added after rank round
+	networkRank.AddNewCids(app.cidNumKeeper.GetCidsCount(ctx))
+
+	hash := networkRank.MerkleTree.RootHash()
+	if !bytes.Equal(hash, app.rankStateKeeper.GetNetworkRankHash()) {
+		return RankSnapshot{}, fmt.Errorf(
+			"recalculated rank hash %s doesn't match network rank hash %s",
+			hex.EncodeToString(hash), hex.EncodeToString(app.rankStateKeeper.GetNetworkRankHash()),
+		)
+	}
+
+	entries := make([]RankSnapshotEntry, 0, len(networkRank.Values))
+	for i, value := range networkRank.Values {
+		number := link.CidNumber(i)
+		entries = append(entries, RankSnapshotEntry{
+			Cid:       app.cidNumKeeper.GetCid(ctx, number),
+			Number:    number,
+			Rank:      value,
+			InDegree:  len(calcCtx.GetInLinks()[number]),
+			OutDegree: len(calcCtx.GetOutLinks()[number]),
+		})
+	}
+
+	return RankSnapshot{Height: height, Hash: hash, Entries: entries}, nil
+}
+
+// Calculates rank of current rank round with live and given params.
+func (app *CyberdApp) SimulateRank(db dbm.DB, dampingFactor, tolerance float64, topSize int) (RankSimulation, error) {
+
+	ctx := app.RpcContext()
+	params := app.rankStateKeeper.GetParams(ctx)
+
+	liveDampingFactor, err := strconv.ParseFloat(params.DampingFactor.String(), 64)
+	if err != nil {
+		return RankSimulation{}, err
+	}
+	liveTolerance, err := strconv.ParseFloat(params.Tolerance.String(), 64)
+	if err != nil {
+		return RankSimulation{}, err
+	}
+
+	height := rankRoundBlockNumber(app.latestBlockHeight, params.CalculationPeriod)
+	rankCtx, err := util.NewContextWithMSVersion(db, height, app.dbKeys.GetStoreKeys()...)
+	if err != nil {
+		return RankSimulation{}, err
+	}
+
+	calcCtx := app.loadRankCalcContext(
+		rankCtx, liveDampingFactor, liveTolerance, params.LinkWeightHalfLife, app.mainKeeper.GetLatestMerkleTreeVersion(ctx),
+	)
+	live := app.simulateRank(ctx, calcCtx, topSize)
+
+	calcCtx.DampingFactor = dampingFactor
+	calcCtx.Tolerance = tolerance
+	simulated := app.simulateRank(ctx, calcCtx, topSize)
+
+	return RankSimulation{Height: height, Live: live, Simulated: simulated}, nil
+}
+
+func (app *CyberdApp) simulateRank(ctx sdk.Context, calcCtx *rank.CalculationContext, topSize int) RankSimulationResult {
+
+	simulatedRank, steps := rank.SimulateRank(calcCtx, app.Logger())
+
+	top := make([]RankedCid, 0, topSize)
+	for _, c := range rank.BuildTopK(simulatedRank.Values, topSize) {
+		top = append(top, RankedCid{Cid: app.cidNumKeeper.GetCid(ctx, c.GetNumber()), Rank: c.GetRank()})
+	}
+
+	return RankSimulationResult{
+		DampingFactor: calcCtx.DampingFactor,
+		Tolerance:     calcCtx.Tolerance,
+		Hash:          simulatedRank.MerkleTree.RootHash(),
+		Iterations:    steps,
+		Top:           top,
+	}
+}
+
+// Network rank is calculated over links and stakes fixed at previous rank round.
+// Used for network rank applied before its calculation height was stored, if previous
+// round started no calculation, rank was calculated at earlier one.
+func (app *CyberdApp) networkRankRoundHeight() (int64, error) {
+
+	ctx := app.NewContext(true, abci.Header{Height: app.latestBlockHeight})
+	calculationPeriod := app.rankStateKeeper.GetParams(ctx).CalculationPeriod
+
+	roundBlockNumber := (app.latestBlockHeight / calculationPeriod) * calculationPeriod
+	switch {
+	case roundBlockNumber < calculationPeriod:
+		return 0, errors.New("network rank is not calculated yet")
+	case roundBlockNumber == calculationPeriod:
+		return 1, nil // special case cause tendermint blocks start from 1
+	default:
+		return roundBlockNumber - calculationPeriod, nil
+	}
+}
+
+// Returns height of current rank calculation round start.
+func rankRoundBlockNumber(latestBlockHeight, calculationPeriod int64) int64 {
+	roundBlockNumber := (latestBlockHeight / calculationPeriod) * calculationPeriod
+	if roundBlockNumber == 0 && latestBlockHeight >= 1 {
+		roundBlockNumber = 1 // special case cause tendermint blocks start from 1
+	}
+	return roundBlockNumber
+}
+
+// Loads links and stakes from given committed state into standalone calculation context.
+func (app *CyberdApp) loadRankCalcContext(
+	rankCtx sdk.Context, dampingFactor, tolerance float64, linkWeightHalfLife int64, treeVersion merkle.Version,
+) *rank.CalculationContext {
+
+	linkIndex := link.NewIndexedKeeper(link.NewLinkKeeper(app.mainKeeper, app.dbKeys.links))
+	linkIndex.Load(rankCtx, rankCtx)
+
+	stakes := make(snapshotStakes)
+	app.accountKeeper.IterateAccounts(rankCtx, func(acc exported.Account) bool {
+		stakes[cbd.AccNumber(acc.GetAccountNumber())] = uint64(app.bankKeeper.GetAccountTotalStake(rankCtx, acc.GetAddress()))
+		return false
+	})
+
+	return rank.NewCalcContext(
+		rankCtx, linkIndex, app.cidNumKeeper, stakes, false, dampingFactor, tolerance, linkWeightHalfLife, treeVersion,
+	)
+}
+
+func (app *CyberdApp) RankParams() rank.Params {
+	return app.rankStateKeeper.GetParams(app.RpcContext())
+}
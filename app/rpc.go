@@ -2,16 +2,15 @@ package app
 
 import (
 	"errors"
-	"math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth/exported"
 	abci "github.com/tendermint/tendermint/abci/types"
 
-	"github.com/cybercongress/go-cyber/merkle"
 	cbd "github.com/cybercongress/go-cyber/types"
 	bw "github.com/cybercongress/go-cyber/x/bandwidth"
 	"github.com/cybercongress/go-cyber/x/link"
+	"github.com/cybercongress/go-cyber/x/rank"
 )
 
 type RankedCid struct {
@@ -63,28 +62,103 @@ func (app *CyberdApp) Top(page, perPage int) ([]RankedCid, int, error) {
 	return result, size, nil
 }
 
-func (app *CyberdApp) Rank(cid string, proof bool) (float64, []merkle.Proof, error) {
+func (app *CyberdApp) PersonalizedRank(cids []string, neurons []sdk.AccAddress, topK int) ([]RankedCid, int, error) {
 
-	cidNumber, exists := app.cidNumKeeper.GetCidNumber(app.RpcContext(), link.Cid(cid))
-	if !exists || cidNumber > app.rankStateKeeper.GetLastCidNum() {
-		return 0, nil, errors.New("no such cid found")
+	ctx := app.RpcContext()
+
+	cidNumbers := make([]link.CidNumber, 0, len(cids))
+	for _, cid := range cids {
+		cidNumber, exists := app.cidNumKeeper.GetCidNumber(ctx, link.Cid(cid))
+		if !exists {
+			return nil, 0, errors.New("no such cid found: " + cid)
+		}
+		cidNumbers = append(cidNumbers, cidNumber)
+	}
+
+	accNumbers := make([]cbd.AccNumber, 0, len(neurons))
+	for _, neuron := range neurons {
+		acc := app.accountKeeper.GetAccount(ctx, neuron)
+		if acc == nil {
+			return nil, 0, errors.New("no such neuron found: " + neuron.String())
+		}
+		accNumbers = append(accNumbers, cbd.AccNumber(acc.GetAccountNumber()))
+	}
+
+	rankedCidNumbers, steps, err := app.rankStateKeeper.PersonalizedRank(ctx, cidNumbers, accNumbers, topK)
+	if err != nil {
+		return nil, steps, err
 	}
 
-	rankValue := app.rankStateKeeper.GetRankValue(cidNumber)
+	result := make([]RankedCid, 0, len(rankedCidNumbers))
+	for _, c := range rankedCidNumbers {
+		result = append(result, RankedCid{Cid: app.cidNumKeeper.GetCid(ctx, c.GetNumber()), Rank: c.GetRank()})
+	}
+
+	return result, steps, nil
+}
+
+// Returns number of cid, which is its leaf index in rank merkle tree, and snapshot of its rank with proofs if requested.
+func (app *CyberdApp) Rank(cid string, proof bool) (link.CidNumber, rank.RankSnapshot, error) {
+
+	cidNumbers, err := app.cidNumbers([]link.Cid{link.Cid(cid)})
+	if err != nil {
+		return 0, rank.RankSnapshot{}, err
+	}
 
+	proofs := rank.WithoutProofs
 	if proof {
-		proofs := make([]merkle.Proof, 0, int64(math.Log2(float64(app.CidsCount()))))
-		proofs = app.rankStateKeeper.GetMerkleTree().GetIndexProofs(int(cidNumber))
-		return rankValue, proofs, nil
+		proofs = rank.WithIndexProofs
+	}
+	snapshot, err := app.rankStateKeeper.GetRankSnapshot(cidNumbers, proofs)
+	return cidNumbers[0], snapshot, err
+}
+
+// Returns ranks of given cids in the same order and snapshot they are taken from,
+// with multiproof of them if requested. Multiproof indices are cids numbers in the same order as cids,
+// proven elements are little endian bits of rank values.
+func (app *CyberdApp) Ranks(cids []link.Cid, proof bool) ([]RankedCid, rank.RankSnapshot, error) {
+
+	cidNumbers, err := app.cidNumbers(cids)
+	if err != nil {
+		return nil, rank.RankSnapshot{}, err
+	}
+
+	proofs := rank.WithoutProofs
+	if proof {
+		proofs = rank.WithMultiProof
+	}
+	snapshot, err := app.rankStateKeeper.GetRankSnapshot(cidNumbers, proofs)
+	if err != nil {
+		return nil, rank.RankSnapshot{}, err
+	}
+
+	result := make([]RankedCid, 0, len(cids))
+	for i, cid := range cids {
+		result = append(result, RankedCid{Cid: cid, Rank: snapshot.Values[i]})
+	}
+	return result, snapshot, nil
+}
+
+func (app *CyberdApp) cidNumbers(cids []link.Cid) ([]link.CidNumber, error) {
+
+	ctx := app.RpcContext()
+
+	cidNumbers := make([]link.CidNumber, 0, len(cids))
+	for _, cid := range cids {
+		cidNumber, exists := app.cidNumKeeper.GetCidNumber(ctx, cid)
+		if !exists {
+			return nil, errors.New("no such cid found: " + string(cid))
+		}
+		cidNumbers = append(cidNumbers, cidNumber)
 	}
-	return rankValue, nil, nil
+	return cidNumbers, nil
 }
 
 func (app *CyberdApp) Account(address sdk.AccAddress) exported.Account {
 	return app.accountKeeper.GetAccount(app.RpcContext(), address)
 }
 
-func (app *CyberdApp) AccountBandwidth(address sdk.AccAddress) bw.AccountBandwidth {
+func (app *CyberdApp) AccountBandwidth(address sdk.AccAddress) (bw.AccountBandwidth, error) {
 	return app.bandwidthMeter.GetCurrentAccBandwidth(app.RpcContext(), address)
 }
 
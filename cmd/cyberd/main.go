@@ -8,6 +8,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 
 	"io"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -35,11 +36,17 @@ const (
 	flagGpuEnabled                = "compute-rank-on-gpu"
 	flagSearchEnabled             = "allow-search"
 	flagInvCheckPeriod            = "inv-check-period"
+	flagPersonalizedRankMaxIters  = "personalized-rank-max-iterations"
+	flagPersonalizedRankMaxCalcs  = "personalized-rank-max-concurrency"
+	flagServeRankProofs           = "serve-rank-proofs"
 )
 
 var invCheckPeriod uint
 var gpuEnabled     bool
 var searchEnabled  bool
+var personalizedRankMaxIters int
+var personalizedRankMaxCalcs int
+var serveRankProofs bool
 
 func main() {
 
@@ -68,6 +75,7 @@ func main() {
 	rootCmd.AddCommand(AddGenesisAccountCmd(ctx, cdc, app.DefaultNodeHome, app.DefaultCLIHome))
 	rootCmd.AddCommand(flags.NewCompletionCmd(rootCmd, true))
 	rootCmd.AddCommand(testnetCmd(ctx, cdc, app.ModuleBasics, auth.GenesisAccountIterator{}))
+	rootCmd.AddCommand(rankCmd(ctx))
 	//rootCmd.AddCommand(replayCmd())
 	rootCmd.AddCommand(debug.Cmd(cdc))
 
@@ -80,6 +88,12 @@ func main() {
 		false, "Enables search API")
 	rootCmd.PersistentFlags().BoolVar(&gpuEnabled, flagGpuEnabled,
 		false, "Runs node in GPU/CPU mode")
+	rootCmd.PersistentFlags().IntVar(&personalizedRankMaxIters, flagPersonalizedRankMaxIters,
+		50, "Max iterations of personalized rank calculation")
+	rootCmd.PersistentFlags().IntVar(&personalizedRankMaxCalcs, flagPersonalizedRankMaxCalcs,
+		0, "Max concurrent personalized rank calculations (0 disables personalized rank API)")
+	rootCmd.PersistentFlags().BoolVar(&serveRankProofs, flagServeRankProofs,
+		false, "Keeps full rank merkle tree in data dir to serve rank proofs without search API")
 	err := executor.Execute()
 	if err != nil {
 		panic(err)
@@ -103,9 +117,15 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		computeUnit = rank.CPU
 	}
 
+	rankProofsDir := ""
+	if serveRankProofs {
+		rankProofsDir = filepath.Join(viper.GetString(cli.HomeFlag), "data", "rank_proofs")
+	}
+
 	cyberdApp := app.NewCyberdApp(
 		logger, db, traceStore, true, invCheckPeriod, skipUpgradeHeights,
 		computeUnit, searchEnabled,
+		rank.NewPersonalizedRankConfig(personalizedRankMaxIters, personalizedRankMaxCalcs), rankProofsDir,
 		baseapp.SetPruning(store.PruneNothing),
 		baseapp.SetMinGasPrices(viper.GetString(server.FlagMinGasPrices)),
 		baseapp.SetHaltHeight(viper.GetUint64(server.FlagHaltHeight)),
@@ -126,11 +146,11 @@ func exportAppStateAndTMValidators(
 	}
 
 	if height != -1 {
-		capp := app.NewCyberdApp(logger, db, traceStore, true, uint(1), map[int64]bool{}, computeUnit, false)
+		capp := app.NewCyberdApp(logger, db, traceStore, true, uint(1), map[int64]bool{}, computeUnit, false, rank.PersonalizedRankConfig{}, "")
 		return capp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 	}
 
-	capp := app.NewCyberdApp(logger, db, traceStore, true, uint(1), map[int64]bool{}, computeUnit, false)
+	capp := app.NewCyberdApp(logger, db, traceStore, true, uint(1), map[int64]bool{}, computeUnit, false, rank.PersonalizedRankConfig{}, "")
 	return capp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 }
 
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cybercongress/go-cyber/app"
+	"github.com/cybercongress/go-cyber/x/rank"
+)
+
+const (
+	flagRankOut = "out"
+	flagRankTop = "top"
+
+	flagRankDamping   = "damping"
+	flagRankTolerance = "tolerance"
+)
+
+// get cmd with offline rank tools, node should be stopped
+func rankCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rank",
+		Short: "Offline rank tools (node should be stopped)",
+	}
+
+	cmd.AddCommand(
+		rankExportCmd(ctx),
+		rankDiffCmd(),
+		rankSimulateCmd(ctx),
+	)
+
+	return cmd
+}
+
+func rankExportCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export current network rank with cids and in/out degrees",
+		Long: `export recalculates current network rank on CPU from node data,
+checks it against network rank hash and writes it to the file.
+File format is selected by extension: .csv or binary for any other.
+
+Example:
+	cyberd rank export --out rank.csv
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			out := viper.GetString(flagRankOut)
+			if out == "" {
+				return fmt.Errorf("--%s flag is required", flagRankOut)
+			}
+
+			db, err := openApplicationDB(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			snapshot, err := newOfflineApp(ctx, db).ExportNetworkRank(db)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]rankExportEntry, 0, len(snapshot.Entries))
+			for _, e := range snapshot.Entries {
+				entries = append(entries, rankExportEntry{
+					Cid:       string(e.Cid),
+					Number:    uint64(e.Number),
+					Rank:      e.Rank,
+					InDegree:  uint32(e.InDegree),
+					OutDegree: uint32(e.OutDegree),
+				})
+			}
+
+			err = writeRankExport(out, entries)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"Exported %d cids of rank calculated at height %d, hash %s\n",
+				len(entries), snapshot.Height, hex.EncodeToString(snapshot.Hash),
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagRankOut, "", "Output file (rank.bin or rank.csv)")
+
+	return cmd
+}
+
+func rankDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Compare two rank exports",
+		Long: `diff reports top movers, new entrants of top and max absolute/relative
+rank change between two rank exports.
+
+Example:
+	cyberd rank diff rank_old.bin rank_new.bin --top 50
+	`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			a, err := readRankExport(args[0])
+			if err != nil {
+				return err
+			}
+			b, err := readRankExport(args[1])
+			if err != nil {
+				return err
+			}
+
+			printRankDiff(diffRankExports(a, b, viper.GetInt(flagRankTop)))
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagRankTop, 20, "Size of top movers and top entrants lists")
+
+	return cmd
+}
+
+func rankSimulateCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate rank of current round with given params",
+		Long: `simulate loads links and stakes of current rank round from node data,
+calculates rank on CPU with live and given params and compares results.
+Params not provided are taken from live params. No consensus state is modified.
+
+Example:
+	cyberd rank simulate --damping 0.8 --tolerance 0.0001 --top 20
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			db, err := openApplicationDB(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			cyberdApp := newOfflineApp(ctx, db)
+			params := cyberdApp.RankParams()
+
+			dampingFactor := viper.GetFloat64(flagRankDamping)
+			if dampingFactor == 0 {
+				dampingFactor, err = strconv.ParseFloat(params.DampingFactor.String(), 64)
+				if err != nil {
+					return err
+				}
+			}
+			tolerance := viper.GetFloat64(flagRankTolerance)
+			if tolerance == 0 {
+				tolerance, err = strconv.ParseFloat(params.Tolerance.String(), 64)
+				if err != nil {
+					return err
+				}
+			}
+			if dampingFactor <= 0 || dampingFactor >= 1 {
+				return fmt.Errorf("damping factor should be in (0, 1), got %v", dampingFactor)
+			}
+			if tolerance <= 0 {
+				return fmt.Errorf("tolerance should be positive, got %v", tolerance)
+			}
+
+			simulation, err := cyberdApp.SimulateRank(db, dampingFactor, tolerance, viper.GetInt(flagRankTop))
+			if err != nil {
+				return err
+			}
+
+			printRankSimulation(simulation)
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64(flagRankDamping, 0, "Damping factor to simulate (live param if not set)")
+	cmd.Flags().Float64(flagRankTolerance, 0, "Tolerance to simulate (live param if not set)")
+	cmd.Flags().Int(flagRankTop, 20, "Size of compared top")
+
+	return cmd
+}
+
+func openApplicationDB(ctx *server.Context) (dbm.DB, error) {
+	dataDir := filepath.Join(ctx.Config.RootDir, "data")
+	return sdk.NewLevelDB("application", dataDir)
+}
+
+// app for offline tools: rank on CPU, no search index and personalized rank
+func newOfflineApp(ctx *server.Context, db dbm.DB) *app.CyberdApp {
+	return app.NewCyberdApp(
+		ctx.Logger, db, nil, true, uint(1), map[int64]bool{}, rank.CPU, false, rank.PersonalizedRankConfig{}, "",
+	)
+}
+
+func printRankSimulation(simulation app.RankSimulation) {
+	fmt.Printf("Links and stakes of rank round at height %d\n", simulation.Height)
+	for _, r := range []struct {
+		name   string
+		result app.RankSimulationResult
+	}{{"Live", simulation.Live}, {"Simulated", simulation.Simulated}} {
+		fmt.Printf(
+			"%s:\tdamping %v, tolerance %v, iterations %d, merkle root %s\n", r.name,
+			r.result.DampingFactor, r.result.Tolerance, r.result.Iterations, hex.EncodeToString(r.result.Hash),
+		)
+	}
+
+	livePositions := make(map[string]int, len(simulation.Live.Top))
+	for i, c := range simulation.Live.Top {
+		livePositions[string(c.Cid)] = i + 1
+	}
+
+	fmt.Println("\nSimulated top (live position in brackets):")
+	for i, c := range simulation.Simulated.Top {
+		livePosition := "-"
+		if position, ok := livePositions[string(c.Cid)]; ok {
+			livePosition = strconv.Itoa(position)
+		}
+		fmt.Printf("  %d [%s]\t%s\t%e\n", i+1, livePosition, c.Cid, c.Rank)
+	}
+}
+
+func printRankDiff(diff rankDiff) {
+	fmt.Printf("Cids: %d -> %d (%d new, %d removed)\n", diff.CountA, diff.CountB, diff.Added, diff.Removed)
+	fmt.Printf("Max absolute change: %e (%s)\n", diff.MaxAbs.Delta, diff.MaxAbs.Cid)
+	fmt.Printf("Max relative change: %.4f%% (%s)\n", diff.MaxRel.Delta*100, diff.MaxRel.Cid)
+
+	fmt.Println("\nTop movers:")
+	for _, m := range diff.Movers {
+		fmt.Printf("  %s\t%e -> %e\t%+e\n", m.Cid, m.RankA, m.RankB, m.Delta)
+	}
+
+	fmt.Println("\nNew entrants of top:")
+	for _, m := range diff.Entrants {
+		fmt.Printf("  %s\t%e -> %e\n", m.Cid, m.RankA, m.RankB)
+	}
+}
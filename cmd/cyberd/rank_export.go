@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cybercongress/go-cyber/util"
+)
+
+// Binary export layout (little endian):
+// entries count uint64, then for each entry:
+// number uint64 | rank float64 bits | in degree uint32 | out degree uint32 | cid length uint16 | cid bytes
+const rankExportEntryHeaderSize = 8 + 8 + 4 + 4 + 2
+
+var rankExportCsvHeader = []string{"cid", "number", "rank", "in_degree", "out_degree"}
+
+type rankExportEntry struct {
+	Cid       string
+	Number    uint64
+	Rank      float64
+	InDegree  uint32
+	OutDegree uint32
+}
+
+func isCsvRankExport(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".csv"
+}
+
+func writeRankExport(path string, entries []rankExportEntry) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if isCsvRankExport(path) {
+		err = writeRankExportCsv(writer, entries)
+	} else {
+		err = writeRankExportBinary(writer, entries)
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func readRankExport(path string) ([]rankExportEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if isCsvRankExport(path) {
+		return readRankExportCsv(reader)
+	}
+	return readRankExportBinary(reader)
+}
+
+func writeRankExportCsv(w io.Writer, entries []rankExportEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(rankExportCsvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.Cid,
+			strconv.FormatUint(e.Number, 10),
+			strconv.FormatFloat(e.Rank, 'g', -1, 64),
+			strconv.FormatUint(uint64(e.InDegree), 10),
+			strconv.FormatUint(uint64(e.OutDegree), 10),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func readRankExportCsv(r io.Reader) ([]rankExportEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(rankExportCsvHeader)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty rank export")
+	}
+
+	entries := make([]rankExportEntry, 0, len(records)-1)
+	for i, record := range records[1:] {
+		number, err := strconv.ParseUint(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		rankValue, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		inDegree, err := strconv.ParseUint(record[3], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		outDegree, err := strconv.ParseUint(record[4], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		entries = append(entries, rankExportEntry{
+			Cid: record[0], Number: number, Rank: rankValue, InDegree: uint32(inDegree), OutDegree: uint32(outDegree),
+		})
+	}
+	return entries, nil
+}
+
+func writeRankExportBinary(w io.Writer, entries []rankExportEntry) error {
+	countBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(countBytes, uint64(len(entries)))
+	if _, err := w.Write(countBytes); err != nil {
+		return err
+	}
+
+	header := make([]byte, rankExportEntryHeaderSize)
+	for _, e := range entries {
+		if len(e.Cid) > math.MaxUint16 {
+			return fmt.Errorf("cid %s is too long", e.Cid)
+		}
+		binary.LittleEndian.PutUint64(header[0:8], e.Number)
+		binary.LittleEndian.PutUint64(header[8:16], math.Float64bits(e.Rank))
+		binary.LittleEndian.PutUint32(header[16:20], e.InDegree)
+		binary.LittleEndian.PutUint32(header[20:24], e.OutDegree)
+		binary.LittleEndian.PutUint16(header[24:26], uint16(len(e.Cid)))
+		if _, err := w.Write(header); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, e.Cid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRankExportBinary(r io.Reader) ([]rankExportEntry, error) {
+	countBytes, err := util.ReadExactlyNBytes(r, 8)
+	if err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(countBytes)
+
+	entries := make([]rankExportEntry, 0, count)
+	for i := uint64(0); i < count; i++ {
+		header, err := util.ReadExactlyNBytes(r, rankExportEntryHeaderSize)
+		if err != nil {
+			return nil, err
+		}
+		cid, err := util.ReadExactlyNBytes(r, uint64(binary.LittleEndian.Uint16(header[24:26])))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, rankExportEntry{
+			Cid:       string(cid),
+			Number:    binary.LittleEndian.Uint64(header[0:8]),
+			Rank:      math.Float64frombits(binary.LittleEndian.Uint64(header[8:16])),
+			InDegree:  binary.LittleEndian.Uint32(header[16:20]),
+			OutDegree: binary.LittleEndian.Uint32(header[20:24]),
+		})
+	}
+	return entries, nil
+}
+
+type rankChange struct {
+	Cid   string
+	RankA float64
+	RankB float64
+	Delta float64
+}
+
+type rankDiff struct {
+	CountA  int
+	CountB  int
+	Added   int
+	Removed int
+
+	MaxAbs rankChange // Delta is absolute change
+	MaxRel rankChange // Delta is change relative to rank in a
+
+	Movers   []rankChange // sorted by absolute change
+	Entrants []rankChange // cids in top of b but not in top of a
+}
+
+// Cids are matched by cid string, missing cids have zero rank.
+func diffRankExports(a, b []rankExportEntry, top int) rankDiff {
+	diff := rankDiff{CountA: len(a), CountB: len(b)}
+
+	ranksA := make(map[string]float64, len(a))
+	for _, e := range a {
+		ranksA[e.Cid] = e.Rank
+	}
+	ranksB := make(map[string]float64, len(b))
+	for _, e := range b {
+		ranksB[e.Cid] = e.Rank
+	}
+	for cid := range ranksA {
+		if _, ok := ranksB[cid]; !ok {
+			diff.Removed++
+		}
+	}
+
+	changes := make([]rankChange, 0, len(b))
+	for _, e := range b {
+		rankA, ok := ranksA[e.Cid]
+		if !ok {
+			diff.Added++
+		}
+		change := rankChange{Cid: e.Cid, RankA: rankA, RankB: e.Rank, Delta: e.Rank - rankA}
+		changes = append(changes, change)
+
+		if math.Abs(change.Delta) > math.Abs(diff.MaxAbs.Delta) {
+			diff.MaxAbs = change
+		}
+		if rankA != 0 {
+			rel := change
+			rel.Delta = change.Delta / rankA
+			if math.Abs(rel.Delta) > math.Abs(diff.MaxRel.Delta) {
+				diff.MaxRel = rel
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return math.Abs(changes[i].Delta) > math.Abs(changes[j].Delta)
+	})
+	if len(changes) > top {
+		changes = changes[:top]
+	}
+	diff.Movers = changes
+
+	topA := make(map[string]struct{}, top)
+	for _, cid := range topRankCids(a, top) {
+		topA[cid] = struct{}{}
+	}
+	for _, cid := range topRankCids(b, top) {
+		if _, ok := topA[cid]; !ok {
+			diff.Entrants = append(diff.Entrants, rankChange{
+				Cid: cid, RankA: ranksA[cid], RankB: ranksB[cid], Delta: ranksB[cid] - ranksA[cid],
+			})
+		}
+	}
+
+	return diff
+}
+
+func topRankCids(entries []rankExportEntry, top int) []string {
+	sorted := make([]rankExportEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Rank > sorted[j].Rank })
+	if len(sorted) > top {
+		sorted = sorted[:top]
+	}
+
+	cids := make([]string, 0, len(sorted))
+	for _, e := range sorted {
+		cids = append(cids, e.Cid)
+	}
+	return cids
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankExportFormat(t *testing.T) {
+	entries := []rankExportEntry{{Cid: "Qm", Number: 7, Rank: 0.5, InDegree: 2, OutDegree: 3}}
+
+	binaryExport := new(bytes.Buffer)
+	require.NoError(t, writeRankExportBinary(binaryExport, entries))
+	require.Equal(t, []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // entries count
+		7, 0, 0, 0, 0, 0, 0, 0, // number
+		0, 0, 0, 0, 0, 0, 0xe0, 0x3f, // rank
+		2, 0, 0, 0, // in degree
+		3, 0, 0, 0, // out degree
+		2, 0, 'Q', 'm', // cid
+	}, binaryExport.Bytes())
+
+	csvExport := new(bytes.Buffer)
+	require.NoError(t, writeRankExportCsv(csvExport, entries))
+	require.Equal(t, "cid,number,rank,in_degree,out_degree\nQm,7,0.5,2,3\n", csvExport.String())
+}
+
+func TestRankExportRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rank_export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	entries := []rankExportEntry{
+		{Cid: "QmZfSNpHVzTNi9gezLcgq64Wbj1xhwi9wk4AxYyxMZgtCG", Number: 0, Rank: 0.1 / 3, InDegree: 5, OutDegree: 1},
+		{Cid: "QmYGKCECAZsT9Pzn1aX5Nf4gKJz7aiWzwvnJH6xJnuoCtd", Number: 1, Rank: 1e-17, InDegree: 0, OutDegree: 4294967295},
+		{Cid: "QmQh3m5EJSdNVbBJRpEM5vvmUmFkrbUNBuJ6HSgPNqUhCL", Number: 18446744073709551615, Rank: 0},
+	}
+
+	for _, name := range []string{"rank.csv", "rank.CSV", "rank.bin", "rank"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, writeRankExport(path, entries), name)
+		read, err := readRankExport(path)
+		require.NoError(t, err, name)
+		require.Equal(t, entries, read, name)
+	}
+
+	empty, err := readRankExportBinary(bytes.NewReader([]byte{0, 0, 0, 0, 0, 0, 0, 0}))
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	// truncated and malformed exports are rejected
+	binaryExport := new(bytes.Buffer)
+	require.NoError(t, writeRankExportBinary(binaryExport, entries))
+	_, err = readRankExportBinary(bytes.NewReader(binaryExport.Bytes()[:binaryExport.Len()-1]))
+	require.Error(t, err)
+	_, err = readRankExportCsv(bytes.NewReader([]byte("cid,number,rank,in_degree,out_degree\nQm,7,x,2,3\n")))
+	require.Error(t, err)
+	_, err = readRankExportCsv(bytes.NewReader([]byte("cid,number,rank,in_degree,out_degree\nQm,7,0.5,2\n")))
+	require.Error(t, err)
+	_, err = readRankExportCsv(bytes.NewReader(nil))
+	require.Error(t, err)
+}
+
+func TestDiffRankExports(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b []rankExportEntry
+		top  int
+		diff rankDiff
+	}{
+		{
+			name: "changed, new and removed cids",
+			a: []rankExportEntry{
+				{Cid: "Q1", Rank: 0.5}, {Cid: "Q2", Rank: 0.25}, {Cid: "Q3", Rank: 0.25}, {Cid: "Q4", Rank: 0},
+			},
+			b: []rankExportEntry{
+				{Cid: "Q1", Rank: 0.375}, {Cid: "Q2", Rank: 0.25}, {Cid: "Q4", Rank: 0.0625}, {Cid: "Q5", Rank: 0.3125},
+			},
+			top: 3,
+			diff: rankDiff{
+				CountA: 4, CountB: 4, Added: 1, Removed: 1,
+				MaxAbs: rankChange{Cid: "Q5", RankA: 0, RankB: 0.3125, Delta: 0.3125},
+				// relative change of zero ranked Q4 and new Q5 isn't defined
+				MaxRel: rankChange{Cid: "Q1", RankA: 0.5, RankB: 0.375, Delta: -0.25},
+				Movers: []rankChange{
+					{Cid: "Q5", RankA: 0, RankB: 0.3125, Delta: 0.3125},
+					{Cid: "Q1", RankA: 0.5, RankB: 0.375, Delta: -0.125},
+					{Cid: "Q4", RankA: 0, RankB: 0.0625, Delta: 0.0625},
+				},
+				Entrants: []rankChange{{Cid: "Q5", RankA: 0, RankB: 0.3125, Delta: 0.3125}},
+			},
+		},
+		{
+			name: "zero ranks",
+			a:    []rankExportEntry{{Cid: "Q1", Rank: 0}, {Cid: "Q2", Rank: 0}},
+			b:    []rankExportEntry{{Cid: "Q1", Rank: 0.75}, {Cid: "Q2", Rank: 0.25}},
+			top:  1,
+			diff: rankDiff{
+				CountA: 2, CountB: 2,
+				MaxAbs: rankChange{Cid: "Q1", RankA: 0, RankB: 0.75, Delta: 0.75},
+				Movers: []rankChange{{Cid: "Q1", RankA: 0, RankB: 0.75, Delta: 0.75}},
+			},
+		},
+		{
+			name: "disjoint exports",
+			a:    []rankExportEntry{{Cid: "Q1", Rank: 1}},
+			b:    []rankExportEntry{{Cid: "Q2", Rank: 1}},
+			top:  5,
+			diff: rankDiff{
+				CountA: 1, CountB: 1, Added: 1, Removed: 1,
+				MaxAbs:   rankChange{Cid: "Q2", RankA: 0, RankB: 1, Delta: 1},
+				Movers:   []rankChange{{Cid: "Q2", RankA: 0, RankB: 1, Delta: 1}},
+				Entrants: []rankChange{{Cid: "Q2", RankA: 0, RankB: 1, Delta: 1}},
+			},
+		},
+		{
+			name: "equal exports",
+			a:    []rankExportEntry{{Cid: "Q1", Rank: 0.5}, {Cid: "Q2", Rank: 0.5}},
+			b:    []rankExportEntry{{Cid: "Q1", Rank: 0.5}, {Cid: "Q2", Rank: 0.5}},
+			top:  1,
+			diff: rankDiff{
+				CountA: 2, CountB: 2,
+				Movers: []rankChange{{Cid: "Q1", RankA: 0.5, RankB: 0.5, Delta: 0}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.diff, diffRankExports(c.a, c.b, c.top), c.name)
+	}
+}
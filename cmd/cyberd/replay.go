@@ -102,7 +102,7 @@ func replayTxs(rootDir string) error {
 	fmt.Fprintln(os.Stderr, "Creating application")
 	gapp := app.NewCyberdApp(
 		ctx.Logger, appDB, traceStoreWriter, true, uint(1), map[int64]bool{},
-		computeUnit, searchEnabled,
+		computeUnit, searchEnabled, rank.PersonalizedRankConfig{}, "",
 		baseapp.SetPruning(store.PruneNothing), // nothing
 	)
 
@@ -40,6 +40,9 @@ func AccountBandwidth(ctx *rpctypes.Context, address string) (*bw.AccountBandwid
 		return nil, err
 	}
 
-	accBdwth := cyberdApp.AccountBandwidth(accAddress)
+	accBdwth, err := cyberdApp.AccountBandwidth(accAddress)
+	if err != nil {
+		return nil, err
+	}
 	return &accBdwth, nil
 }
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/cybercongress/go-cyber/app"
+)
+
+type ResultPersonalizedRank struct {
+	Cids       []app.RankedCid `json:"cids"`
+	Iterations int             `json:"iterations"`
+}
+
+const maxPersonalizedRankTopK = 1000
+
+func PersonalizedRank(ctx *rpctypes.Context, cids []string, neurons []string, topK int) (*ResultPersonalizedRank, error) {
+	if topK == 0 {
+		topK = 100
+	}
+	if topK < 0 || topK > maxPersonalizedRankTopK {
+		return nil, fmt.Errorf("top k should be in range 1..%d", maxPersonalizedRankTopK)
+	}
+
+	accAddresses := make([]types.AccAddress, 0, len(neurons))
+	for _, neuron := range neurons {
+		accAddress, err := types.AccAddressFromBech32(neuron)
+		if err != nil {
+			return nil, err
+		}
+		accAddresses = append(accAddresses, accAddress)
+	}
+
+	rankedCids, iterations, err := cyberdApp.PersonalizedRank(cids, accAddresses, topK)
+	return &ResultPersonalizedRank{rankedCids, iterations}, err
+}
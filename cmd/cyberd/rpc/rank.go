@@ -1,17 +1,62 @@
 package rpc
 
 import (
+	"errors"
+
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 
+	"github.com/cybercongress/go-cyber/app"
 	"github.com/cybercongress/go-cyber/merkle"
+	"github.com/cybercongress/go-cyber/x/link"
 )
 
 type RankAndProofResult struct {
 	Proofs []merkle.Proof `json:"proofs"`
 	Rank   float64        `amino:"unsafe" json:"rank"`
+	// height of block rank merkle tree was produced at the end of, tree is stored in state of this block
+	Height int64 `json:"height"`
+	// hashing version of rank merkle tree, required to verify proofs
+	TreeVersion merkle.Version `json:"treeVersion"`
+	// leaf index of cid and leaves count of rank merkle tree, required to verify proofs of cid.
+	// They aren't committed by app hash, which is root hash of rank merkle tree.
+	CidNumber   uint64 `json:"cidNumber,omitempty"`
+	LeavesCount int    `json:"leavesCount,omitempty"`
+
+	// filled only for cids list, in the same order as requested
+	Cids       []app.RankedCid    `json:"cids,omitempty"`
+	MultiProof *merkle.MultiProof `json:"multiProof,omitempty"`
 }
 
-func Rank(ctx *rpctypes.Context, cid string, proof bool) (*RankAndProofResult, error) {
-	rankValue, proofs, err := cyberdApp.Rank(cid, proof)
-	return &RankAndProofResult{proofs, rankValue}, err
+// Rank values, proofs and tree parameters are taken from the same rank merkle tree.
+func Rank(ctx *rpctypes.Context, cid string, proof bool, cids []string) (*RankAndProofResult, error) {
+
+	if len(cids) != 0 {
+		if cid != "" {
+			return nil, errors.New("either cid or cids should be provided")
+		}
+		linkCids := make([]link.Cid, 0, len(cids))
+		for _, c := range cids {
+			linkCids = append(linkCids, link.Cid(c))
+		}
+		rankedCids, snapshot, err := cyberdApp.Ranks(linkCids, proof)
+		if err != nil {
+			return nil, err
+		}
+		return &RankAndProofResult{
+			Height: snapshot.Height, TreeVersion: snapshot.TreeVersion, Cids: rankedCids, MultiProof: snapshot.MultiProof,
+		}, nil
+	}
+
+	cidNumber, snapshot, err := cyberdApp.Rank(cid, proof)
+	if err != nil {
+		return nil, err
+	}
+	result := &RankAndProofResult{
+		Proofs: snapshot.Proofs, Rank: snapshot.Values[0], Height: snapshot.Height, TreeVersion: snapshot.TreeVersion,
+	}
+	if proof {
+		result.CidNumber = uint64(cidNumber)
+		result.LeavesCount = snapshot.LeavesCount
+	}
+	return result, nil
 }
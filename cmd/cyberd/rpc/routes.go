@@ -14,14 +14,16 @@ func SetCyberdApp(cApp *app.CyberdApp) {
 }
 
 var Routes = map[string]*rpcserver.RPCFunc{
-	"search":                  rpcserver.NewRPCFunc(Search, "cid,page,perPage"),
+	"search":                  rpcserver.NewRPCFunc(Search, "cid,page,perPage,proof"),
 	"top":                     rpcserver.NewRPCFunc(Top, "page,perPage"),
-	"rank":                    rpcserver.NewRPCFunc(Rank, "cid,proof"),
+	"rank":                    rpcserver.NewRPCFunc(Rank, "cid,proof,cids"),
+	"personalized_rank":       rpcserver.NewRPCFunc(PersonalizedRank, "cids,neurons,topK"),
 	"account":                 rpcserver.NewRPCFunc(Account, "address"),
 	"account_bandwidth":       rpcserver.NewRPCFunc(AccountBandwidth, "address"),
 	"is_link_exist":           rpcserver.NewRPCFunc(IsLinkExist, "from,to,address"),
 	"current_bandwidth_price": rpcserver.NewRPCFunc(CurrentBandwidthPrice, ""),
 	"current_network_load":    rpcserver.NewRPCFunc(CurrentNetworkLoad, ""),
+	"simulate_bandwidth":      rpcserver.NewRPCFunc(SimulateBandwidth, "tx"),
 	"index_stats":             rpcserver.NewRPCFunc(IndexStats, ""),
 
 	// TODO remove this for euler-6 release
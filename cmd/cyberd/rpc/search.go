@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"github.com/cybercongress/go-cyber/app"
+	"github.com/cybercongress/go-cyber/merkle"
+	"github.com/cybercongress/go-cyber/x/link"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 )
 
@@ -10,12 +12,32 @@ type ResultSearch struct {
 	TotalCount int             `json:"total"`
 	Page       int             `json:"page"`
 	PerPage    int             `json:"perPage"`
+	// multiproof of found cids ranks, indices are in the same order as cids.
+	// Ranks are replaced by proven ones then, tree is produced at the end of block of given height.
+	Proof       *merkle.MultiProof `json:"proof,omitempty"`
+	TreeVersion merkle.Version     `json:"treeVersion,omitempty"`
+	Height      int64              `json:"height,omitempty"`
 }
 
-func Search(ctx *rpctypes.Context, cid string, page, perPage int) (*ResultSearch, error) {
+func Search(ctx *rpctypes.Context, cid string, page, perPage int, proof bool) (*ResultSearch, error) {
 	if perPage == 0 {
 		perPage = 100
 	}
 	links, totalSize, err := cyberdApp.Search(cid, page, perPage)
-	return &ResultSearch{links, totalSize, page, perPage}, err
+	if err != nil || !proof {
+		return &ResultSearch{links, totalSize, page, perPage, nil, 0, 0}, err
+	}
+
+	// search index is updated asynchronously, so proven ranks could differ from found ones
+	cids := make([]link.Cid, 0, len(links))
+	for _, l := range links {
+		cids = append(cids, l.Cid)
+	}
+	provenLinks, snapshot, err := cyberdApp.Ranks(cids, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultSearch{
+		provenLinks, totalSize, page, perPage, snapshot.MultiProof, snapshot.TreeVersion, snapshot.Height,
+	}, nil
 }
@@ -0,0 +1,16 @@
+package rpc
+
+import (
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/cybercongress/go-cyber/app"
+)
+
+// Takes amino encoded tx (could be unsigned), returns bandwidth it would consume.
+func SimulateBandwidth(ctx *rpctypes.Context, tx []byte) (*app.BandwidthEstimation, error) {
+	estimation, err := cyberdApp.SimulateBandwidth(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &estimation, nil
+}
@@ -9,5 +9,5 @@ func Top(ctx *rpctypes.Context, page, perPage int) (*ResultSearch, error) {
 		perPage = 100
 	}
 	cids, totalSize, err := cyberdApp.Top(page, perPage)
-	return &ResultSearch{cids, totalSize, page, perPage}, err
+	return &ResultSearch{cids, totalSize, page, perPage, nil, 0, 0}, err
 }
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+
+	"github.com/cybercongress/go-cyber/app"
+)
+
+const FlagEstimateBandwidth = "estimate-bandwidth"
+
+// Builds and broadcasts tx the same as utils.CompleteAndBroadcastTxCLI,
+// but if --estimate-bandwidth is set prints bandwidth tx would consume instead.
+func CompleteAndBroadcastTxCLI(txBldr auth.TxBuilder, cliCtx context.CLIContext, msgs []sdk.Msg) error {
+
+	if !viper.GetBool(FlagEstimateBandwidth) {
+		return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, msgs)
+	}
+	return estimateMsgsBandwidth(txBldr, cliCtx, msgs)
+}
+
+// Generates or broadcasts tx the same as utils.GenerateOrBroadcastMsgs,
+// but if --estimate-bandwidth is set prints bandwidth tx would consume instead.
+func GenerateOrBroadcastMsgs(cliCtx context.CLIContext, txBldr auth.TxBuilder, msgs []sdk.Msg) error {
+
+	if !viper.GetBool(FlagEstimateBandwidth) {
+		return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, msgs)
+	}
+	return estimateMsgsBandwidth(txBldr, cliCtx, msgs)
+}
+
+func estimateMsgsBandwidth(txBldr auth.TxBuilder, cliCtx context.CLIContext, msgs []sdk.Msg) error {
+
+	txBldr, err := utils.PrepareTxBuilder(txBldr, cliCtx)
+	if err != nil {
+		return err
+	}
+
+	stdSignMsg, err := txBldr.BuildSignMsg(msgs)
+	if err != nil {
+		return err
+	}
+
+	// bandwidth doesn't depend on signatures
+	stdTx := auth.NewStdTx(stdSignMsg.Msgs, stdSignMsg.Fee, nil, stdSignMsg.Memo)
+	return estimateBandwidth(cliCtx, utils.GetTxEncoder(cliCtx.Codec), stdTx)
+}
+
+// EstimateBandwidthCmd prints bandwidth of tx generated by any tx command with --generate-only.
+func EstimateBandwidthCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate-bandwidth [file]",
+		Short: "Estimate bandwidth of tx generated offline (signatures are not required)",
+		Long: `Estimate bandwidth of tx generated offline (signatures are not required).
+Link and send commands estimate bandwidth with --estimate-bandwidth flag, for other
+tx commands generate tx with --generate-only and pass its file to this command.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			stdTx, err := utils.ReadStdTxFromFile(cliCtx.Codec, args[0])
+			if err != nil {
+				return err
+			}
+
+			return estimateBandwidth(cliCtx, utils.GetTxEncoder(cdc), stdTx)
+		},
+	}
+
+	return flags.GetCommands(cmd)[0]
+}
+
+func estimateBandwidth(cliCtx context.CLIContext, txEncoder sdk.TxEncoder, stdTx auth.StdTx) error {
+
+	txBytes, err := txEncoder(stdTx)
+	if err != nil {
+		return err
+	}
+
+	client, err := rpcclient.New(cliCtx.NodeURI)
+	if err != nil {
+		return err
+	}
+
+	var result app.BandwidthEstimation
+	_, err = client.Call("simulate_bandwidth", map[string]interface{}{"tx": txBytes}, &result)
+	if err != nil {
+		return err
+	}
+
+	return cliCtx.PrintOutput(result)
+}
@@ -55,12 +55,13 @@ func LinkTxCmd(cdc *codec.Codec) *cobra.Command {
 			// build and sign the transaction, then broadcast to Tendermint
 			msg := link.NewMsg(signAddr, []link.Link{{From: cidFrom, To: cidTo}})
 
-			return utils.CompleteAndBroadcastTxCLI(txCtx, cliCtx, []sdk.Msg{msg})
+			return CompleteAndBroadcastTxCLI(txCtx, cliCtx, []sdk.Msg{msg})
 		},
 	}
 
 	cmd.Flags().String(flagCidFrom, "", "Content id to link from")
 	cmd.Flags().String(flagCidTo, "", "Content id to link to")
+	cmd.Flags().Bool(FlagEstimateBandwidth, false, "Print bandwidth tx would consume instead of broadcasting it")
 
 	return cmd
 }
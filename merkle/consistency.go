@@ -0,0 +1,220 @@
+package merkle
+
+import (
+	"bytes"
+	"hash"
+)
+
+// Proof that tree of NewSize elements extends tree of OldSize elements (RFC-6962 section 2.1.2 analogue).
+// Subtrees of old tree are nodes of new tree, so new root hash is calculated from old subtrees roots
+// and hashes of nodes with elements added after old tree.
+type ConsistencyProof struct {
+	OldSize int `json:"oldSize"`
+	NewSize int `json:"newSize"`
+	// old tree subtrees roots from left to right (omitted if old tree is single subtree),
+	// then hashes of new tree nodes without old elements in depth-first order (first summand first)
+	Hashes [][]byte `json:"hashes"`
+}
+
+// Returns nil for not full tree or if sizes are not 0 < oldSize <= newSize <= tree elements count.
+func (t *Tree) GetConsistencyProof(oldSize, newSize int) *ConsistencyProof {
+
+	// we cannot build proofs with not full tree
+	if !t.full || oldSize <= 0 || oldSize > newSize || newSize > t.lastIndex {
+		return nil
+	}
+
+	hashes := make([][]byte, 0)
+
+	oldSizes := subtreesSizes(oldSize)
+	if len(oldSizes) > 1 {
+		first := 0
+		for _, size := range oldSizes {
+			hashes = append(hashes, t.findNode(first, size).hash)
+			first += size
+		}
+	}
+
+	return &ConsistencyProof{
+		OldSize: oldSize,
+		NewSize: newSize,
+		Hashes:  t.collectSubtreesConsistencyProof(0, subtreesSizes(newSize), oldSize, hashes),
+	}
+}
+
+func (t *Tree) collectSubtreesConsistencyProof(first int, sizes []int, oldSize int, hashes [][]byte) [][]byte {
+
+	if first >= oldSize {
+		// sum of subtrees from right to left
+		n := len(sizes) - 1
+		subtreesFirst := make([]int, len(sizes))
+		for i := 1; i <= n; i++ {
+			subtreesFirst[i] = subtreesFirst[i-1] + sizes[i-1]
+		}
+		rootHash := t.findNode(first+subtreesFirst[n], sizes[n]).hash
+		for i := n - 1; i >= 0; i-- {
+			rootHash = nodeSum(t.hashF, t.version, rootHash, t.findNode(first+subtreesFirst[i], sizes[i]).hash)
+		}
+		return append(hashes, rootHash)
+	}
+
+	if len(sizes) == 1 {
+		return t.collectNodeConsistencyProof(first, sizes[0], oldSize, hashes)
+	}
+
+	hashes = t.collectSubtreesConsistencyProof(first+sizes[0], sizes[1:], oldSize, hashes)
+	return t.collectNodeConsistencyProof(first, sizes[0], oldSize, hashes)
+}
+
+func (t *Tree) collectNodeConsistencyProof(first int, size int, oldSize int, hashes [][]byte) [][]byte {
+
+	if first >= oldSize {
+		return append(hashes, t.findNode(first, size).hash)
+	}
+
+	// node is old tree subtree, its hash is known from old tree
+	if isOldSubtree(first, size, oldSize) {
+		return hashes
+	}
+
+	hashes = t.collectNodeConsistencyProof(first, size/2, oldSize, hashes)
+	return t.collectNodeConsistencyProof(first+size/2, size/2, oldSize, hashes)
+}
+
+// Returns node with given elements range. Node should exist in tree.
+func (t *Tree) findNode(first int, size int) *Node {
+
+	current := t.subTree
+	for current != nil && current.root.firstIndex > first {
+		current = current.left
+	}
+
+	node := current.root
+	for node.lastIndex-node.firstIndex+1 > size {
+		if first <= node.left.lastIndex {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return node
+}
+
+// Verifies that tree with new root hash extends tree with old root hash.
+func VerifyConsistencyProof(
+	hashF hash.Hash, version Version, oldRootHash []byte, newRootHash []byte, proof ConsistencyProof,
+) bool {
+
+	if proof.OldSize <= 0 || proof.OldSize > proof.NewSize {
+		return false
+	}
+
+	v := &consistencyProofVerifier{
+		hashF:    hashF,
+		version:  version,
+		oldSize:  proof.OldSize,
+		oldRoots: make(map[int][]byte),
+		hashes:   proof.Hashes,
+	}
+
+	oldSizes := subtreesSizes(proof.OldSize)
+	if len(oldSizes) == 1 {
+		v.oldRoots[0] = oldRootHash
+	} else {
+		first := 0
+		for _, size := range oldSizes {
+			oldRoot, ok := v.nextHash()
+			if !ok {
+				return false
+			}
+			v.oldRoots[first] = oldRoot
+			first += size
+		}
+
+		// old root is sum of subtrees roots from right to left
+		n := len(oldSizes) - 1
+		calculatedOldRootHash := proof.Hashes[n]
+		for i := n - 1; i >= 0; i-- {
+			calculatedOldRootHash = nodeSum(hashF, version, calculatedOldRootHash, proof.Hashes[i])
+		}
+		if !bytes.Equal(calculatedOldRootHash, oldRootHash) {
+			return false
+		}
+	}
+
+	calculatedNewRootHash, ok := v.subtreesHash(0, subtreesSizes(proof.NewSize))
+	return ok && v.next == len(proof.Hashes) && bytes.Equal(calculatedNewRootHash, newRootHash)
+}
+
+type consistencyProofVerifier struct {
+	hashF    hash.Hash
+	version  Version
+	oldSize  int
+	oldRoots map[int][]byte // old subtrees roots by subtree first index
+	hashes   [][]byte
+	next     int
+}
+
+func (v *consistencyProofVerifier) nextHash() ([]byte, bool) {
+	if v.next >= len(v.hashes) {
+		return nil, false
+	}
+	v.next++
+	return v.hashes[v.next-1], true
+}
+
+func (v *consistencyProofVerifier) subtreesHash(first int, sizes []int) ([]byte, bool) {
+
+	if first >= v.oldSize {
+		return v.nextHash()
+	}
+
+	if len(sizes) == 1 {
+		return v.nodeHash(first, sizes[0])
+	}
+
+	rightHash, ok := v.subtreesHash(first+sizes[0], sizes[1:])
+	if !ok {
+		return nil, false
+	}
+	leftHash, ok := v.nodeHash(first, sizes[0])
+	if !ok {
+		return nil, false
+	}
+
+	return nodeSum(v.hashF, v.version, rightHash, leftHash), true
+}
+
+func (v *consistencyProofVerifier) nodeHash(first int, size int) ([]byte, bool) {
+
+	if first >= v.oldSize {
+		return v.nextHash()
+	}
+
+	if isOldSubtree(first, size, v.oldSize) {
+		return v.oldRoots[first], true
+	}
+
+	leftHash, ok := v.nodeHash(first, size/2)
+	if !ok {
+		return nil, false
+	}
+	rightHash, ok := v.nodeHash(first+size/2, size/2)
+	if !ok {
+		return nil, false
+	}
+
+	return nodeSum(v.hashF, v.version, leftHash, rightHash), true
+}
+
+// checks elements range is one of subtrees of tree with given elements count
+func isOldSubtree(first int, size int, oldSize int) bool {
+	subtreeFirst := 0
+	for _, subtreeSize := range subtreesSizes(oldSize) {
+		if subtreeFirst == first {
+			return subtreeSize == size
+		}
+		subtreeFirst += subtreeSize
+	}
+	return false
+}
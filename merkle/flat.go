@@ -0,0 +1,334 @@
+package merkle
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Full tree stored as flat array of nodes hashes level by level, starting from elements hashes.
+// As all subtrees have power of 2 elements, level l consists of nodes of first leavesCount>>l
+// aligned ranges of 2^l elements, so node position is calculated from its level and index.
+// Proofs are read from storage without holding tree in memory, only nodes of elements pushed
+// after tree was written are kept in memory.
+type FlatTree struct {
+	mtx sync.Mutex
+
+	r io.ReaderAt
+	// DON'T USE IT FOR PARALLEL CALCULATION, guarded by mtx
+	hashF   hash.Hash
+	version Version
+
+	hashSize    int
+	leavesCount int
+
+	// stored nodes count and offset of each level
+	storedCounts  []int
+	storedOffsets []int64
+	// nodes of each level added by pushes after tree was written
+	pushed [][][]byte
+}
+
+const flatTreeFormat = 1
+
+// format, hashing version and elements count
+const flatTreeHeaderSize = 1 + 1 + 8
+
+// Writes full tree with data as elements in flat format. Only one level is kept in memory.
+func WriteFlatTree(w io.Writer, hashF hash.Hash, version Version, data [][]byte) error {
+
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, flatTreeHeaderSize)
+	header[0] = flatTreeFormat
+	header[1] = byte(version)
+	binary.LittleEndian.PutUint64(header[2:], uint64(len(data)))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	level := make([][]byte, len(data))
+	for i, d := range data {
+		level[i] = leafSum(hashF, version, d)
+	}
+
+	for len(level) != 0 {
+		for _, h := range level {
+			if _, err := bw.Write(h); err != nil {
+				return err
+			}
+		}
+
+		nextLevel := make([][]byte, len(level)/2)
+		for i := range nextLevel {
+			nextLevel[i] = nodeSum(hashF, version, level[2*i], level[2*i+1])
+		}
+		level = nextLevel
+	}
+
+	return bw.Flush()
+}
+
+// Opens tree written by WriteFlatTree, size is size of written data.
+func NewFlatTree(r io.ReaderAt, size int64, hashF hash.Hash) (*FlatTree, error) {
+
+	header := make([]byte, flatTreeHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if header[0] != flatTreeFormat {
+		return nil, fmt.Errorf("unsupported flat tree format %d", header[0])
+	}
+	version := Version(header[1])
+	if !version.IsValid() {
+		return nil, fmt.Errorf("unsupported tree version %d", version)
+	}
+
+	t := &FlatTree{
+		r:           r,
+		hashF:       hashF,
+		version:     version,
+		hashSize:    hashF.Size(),
+		leavesCount: int(binary.LittleEndian.Uint64(header[2:])),
+	}
+
+	offset := int64(flatTreeHeaderSize)
+	for count := t.leavesCount; count != 0; count >>= 1 {
+		t.storedCounts = append(t.storedCounts, count)
+		t.storedOffsets = append(t.storedOffsets, offset)
+		t.pushed = append(t.pushed, nil)
+		offset += int64(count) * int64(t.hashSize)
+	}
+
+	if offset != size {
+		return nil, fmt.Errorf("flat tree size %d doesn't match %d elements", size, t.leavesCount)
+	}
+
+	return t, nil
+}
+
+func (t *FlatTree) Version() Version {
+	return t.version
+}
+
+func (t *FlatTree) LeavesCount() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.leavesCount
+}
+
+func (t *FlatTree) node(level, index int) ([]byte, error) {
+
+	if level < len(t.storedCounts) && index < t.storedCounts[level] {
+		nodeHash := make([]byte, t.hashSize)
+		_, err := t.r.ReadAt(nodeHash, t.storedOffsets[level]+int64(index)*int64(t.hashSize))
+		return nodeHash, err
+	}
+
+	storedCount := 0
+	if level < len(t.storedCounts) {
+		storedCount = t.storedCounts[level]
+	}
+	return t.pushed[level][index-storedCount], nil
+}
+
+// Adds element to the end of tree, new nodes are kept in memory.
+func (t *FlatTree) Push(data []byte) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	index := t.leavesCount
+	nodeHash := leafSum(t.hashF, t.version, data)
+
+	// node completes parent node if it is right child
+	for level := 0; ; level++ {
+		if level == len(t.pushed) {
+			t.pushed = append(t.pushed, nil)
+		}
+		t.pushed[level] = append(t.pushed[level], nodeHash)
+
+		if index&1 == 0 {
+			break
+		}
+		left, err := t.node(level, index-1)
+		if err != nil {
+			return err
+		}
+		nodeHash = nodeSum(t.hashF, t.version, left, nodeHash)
+		index >>= 1
+	}
+
+	t.leavesCount++
+	return nil
+}
+
+// subtrees roots from left to right
+func (t *FlatTree) subtreesRoots() ([][]byte, []int, error) {
+	sizes := subtreesSizes(t.leavesCount)
+	roots := make([][]byte, len(sizes))
+	first := 0
+	for i, size := range sizes {
+		level := log2(size)
+		root, err := t.node(level, first>>level)
+		if err != nil {
+			return nil, nil, err
+		}
+		roots[i] = root
+		first += size
+	}
+	return roots, sizes, nil
+}
+
+func (t *FlatTree) RootHash() ([]byte, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.leavesCount == 0 {
+		return sum(t.hashF), nil // zero hash
+	}
+
+	roots, _, err := t.subtreesRoots()
+	if err != nil {
+		return nil, err
+	}
+	return t.rootsHash(roots), nil
+}
+
+// root hash calculates from right to left by summing subtrees root hashes.
+func (t *FlatTree) rootsHash(roots [][]byte) []byte {
+	n := len(roots) - 1
+	rootHash := roots[n]
+	for i := n - 1; i >= 0; i-- {
+		rootHash = nodeSum(t.hashF, t.version, rootHash, roots[i])
+	}
+	return rootHash
+}
+
+// Returns the same proofs as Tree.GetIndexProofs of full tree with the same elements.
+func (t *FlatTree) GetIndexProofs(i int) ([]Proof, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if i < 0 || i >= t.leavesCount {
+		return nil, errors.New("index is out of tree")
+	}
+
+	roots, sizes, err := t.subtreesRoots()
+	if err != nil {
+		return nil, err
+	}
+
+	subtree, first := 0, 0
+	for i >= first+sizes[subtree] {
+		first += sizes[subtree]
+		subtree++
+	}
+
+	proofs := make([]Proof, 0)
+	for level := 0; level < log2(sizes[subtree]); level++ {
+		index := i >> level
+		sibling, err := t.node(level, index^1)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, Proof{Hash: sibling, LeftSide: index&1 == 1})
+	}
+
+	if subtree+1 < len(roots) {
+		proofs = append(proofs, Proof{Hash: t.rootsHash(roots[subtree+1:]), LeftSide: true})
+	}
+	for j := subtree - 1; j >= 0; j-- {
+		proofs = append(proofs, Proof{Hash: roots[j], LeftSide: false})
+	}
+
+	return proofs, nil
+}
+
+// Returns the same proof as Tree.GetMultiProof of full tree with the same elements.
+func (t *FlatTree) GetMultiProof(indices []int) (*MultiProof, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.leavesCount == 0 {
+		return nil, errors.New("tree is empty")
+	}
+	for _, i := range indices {
+		if i < 0 || i >= t.leavesCount {
+			return nil, errors.New("index is out of tree")
+		}
+	}
+
+	roots, sizes, err := t.subtreesRoots()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := t.collectSubtreesProof(roots, sizes, 0, sortedUniqueIndices(indices), make([][]byte, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	provenIndices := make([]int, len(indices))
+	copy(provenIndices, indices)
+
+	return &MultiProof{LeavesCount: t.leavesCount, Indices: provenIndices, Hashes: hashes}, nil
+}
+
+func (t *FlatTree) collectSubtreesProof(
+	roots [][]byte, sizes []int, first int, indices []int, hashes [][]byte,
+) ([][]byte, error) {
+
+	if len(indices) == 0 {
+		return append(hashes, t.rootsHash(roots)), nil
+	}
+
+	if len(sizes) == 1 {
+		return t.collectNodeProof(log2(sizes[0]), first, indices, hashes)
+	}
+
+	split := sort.SearchInts(indices, first+sizes[0])
+	hashes, err := t.collectSubtreesProof(roots[1:], sizes[1:], first+sizes[0], indices[split:], hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.collectNodeProof(log2(sizes[0]), first, indices[:split], hashes)
+}
+
+func (t *FlatTree) collectNodeProof(level int, first int, indices []int, hashes [][]byte) ([][]byte, error) {
+
+	if len(indices) == 0 {
+		nodeHash, err := t.node(level, first>>level)
+		if err != nil {
+			return nil, err
+		}
+		return append(hashes, nodeHash), nil
+	}
+
+	// leaf hash is calculated from proven element
+	if level == 0 {
+		return hashes, nil
+	}
+
+	half := 1 << (level - 1)
+	split := sort.SearchInts(indices, first+half)
+	hashes, err := t.collectNodeProof(level-1, first, indices[:split], hashes)
+	if err != nil {
+		return nil, err
+	}
+	return t.collectNodeProof(level-1, first+half, indices[split:], hashes)
+}
+
+func log2(size int) int {
+	level := 0
+	for size > 1 {
+		size >>= 1
+		level++
+	}
+	return level
+}
@@ -0,0 +1,182 @@
+package merkle
+
+import (
+	"bytes"
+	"hash"
+	"sort"
+)
+
+// Compact proof for a set of indices. Nodes shared by proven indices paths are included once,
+// nodes derivable from proven elements are not included at all.
+type MultiProof struct {
+	// elements count of tree, defines tree shape
+	LeavesCount int `json:"leavesCount"`
+	// proven indices in order data should be passed to verification (could be unsorted)
+	Indices []int `json:"indices"`
+	// hashes of nodes without proven indices in depth-first order (first summand first)
+	Hashes [][]byte `json:"hashes"`
+}
+
+// Returns nil for not full tree or if any index is out of tree.
+func (t *Tree) GetMultiProof(indices []int) *MultiProof {
+
+	// we cannot build proofs with not full tree
+	if !t.full || t.subTree == nil {
+		return nil
+	}
+
+	for _, i := range indices {
+		if i < 0 || i >= t.lastIndex {
+			return nil
+		}
+	}
+
+	// from left to right, the same order as subtrees indices
+	subtrees := make([]*Subtree, t.subTreesCount)
+	current := t.subTree
+	for i := t.subTreesCount - 1; i >= 0; i-- {
+		subtrees[i] = current
+		current = current.left
+	}
+
+	provenIndices := make([]int, len(indices))
+	copy(provenIndices, indices)
+
+	return &MultiProof{
+		LeavesCount: t.lastIndex,
+		Indices:     provenIndices,
+		Hashes:      t.collectSubtreesProof(subtrees, sortedUniqueIndices(indices), make([][]byte, 0)),
+	}
+}
+
+// root hash of subtrees is sum of right subtrees hash (first summand) and leftmost subtree root hash
+func (t *Tree) collectSubtreesProof(subtrees []*Subtree, indices []int, hashes [][]byte) [][]byte {
+
+	if len(indices) == 0 {
+		return append(hashes, t.subtreesRootHash(subtrees))
+	}
+
+	if len(subtrees) == 1 {
+		return subtrees[0].root.collectMultiProof(indices, hashes)
+	}
+
+	split := sort.SearchInts(indices, subtrees[1].root.firstIndex)
+	hashes = t.collectSubtreesProof(subtrees[1:], indices[split:], hashes)
+
+	return subtrees[0].root.collectMultiProof(indices[:split], hashes)
+}
+
+func (t *Tree) subtreesRootHash(subtrees []*Subtree) []byte {
+	n := len(subtrees) - 1
+	rootHash := subtrees[n].root.hash
+	for i := n - 1; i >= 0; i-- {
+		rootHash = nodeSum(t.hashF, t.version, rootHash, subtrees[i].root.hash)
+	}
+	return rootHash
+}
+
+// Verifies that data are elements at proof indices of tree with given root hash.
+// data[i] is element at proof.Indices[i].
+func VerifyMultiProof(hashF hash.Hash, version Version, rootHash []byte, proof MultiProof, data [][]byte) bool {
+
+	if len(proof.Indices) != len(data) || proof.LeavesCount <= 0 {
+		return false
+	}
+
+	elements := make(map[int][]byte, len(data))
+	for i, index := range proof.Indices {
+		if index < 0 || index >= proof.LeavesCount {
+			return false
+		}
+		if existing, ok := elements[index]; ok && !bytes.Equal(existing, data[i]) {
+			return false
+		}
+		elements[index] = data[i]
+	}
+
+	v := &multiProofVerifier{hashF: hashF, version: version, elements: elements, hashes: proof.Hashes}
+	calculatedRootHash, ok := v.subtreesHash(0, subtreesSizes(proof.LeavesCount), sortedUniqueIndices(proof.Indices))
+
+	return ok && v.next == len(proof.Hashes) && bytes.Equal(calculatedRootHash, rootHash)
+}
+
+type multiProofVerifier struct {
+	hashF    hash.Hash
+	version  Version
+	elements map[int][]byte
+	hashes   [][]byte
+	next     int
+}
+
+func (v *multiProofVerifier) nextHash() ([]byte, bool) {
+	if v.next >= len(v.hashes) {
+		return nil, false
+	}
+	v.next++
+	return v.hashes[v.next-1], true
+}
+
+func (v *multiProofVerifier) subtreesHash(firstIndex int, sizes []int, indices []int) ([]byte, bool) {
+
+	if len(indices) == 0 {
+		return v.nextHash()
+	}
+
+	if len(sizes) == 1 {
+		return v.nodeHash(firstIndex, sizes[0], indices)
+	}
+
+	restFirstIndex := firstIndex + sizes[0]
+	split := sort.SearchInts(indices, restFirstIndex)
+
+	rightHash, ok := v.subtreesHash(restFirstIndex, sizes[1:], indices[split:])
+	if !ok {
+		return nil, false
+	}
+
+	leftHash, ok := v.nodeHash(firstIndex, sizes[0], indices[:split])
+	if !ok {
+		return nil, false
+	}
+
+	return nodeSum(v.hashF, v.version, rightHash, leftHash), true
+}
+
+func (v *multiProofVerifier) nodeHash(firstIndex int, size int, indices []int) ([]byte, bool) {
+
+	if len(indices) == 0 {
+		return v.nextHash()
+	}
+
+	if size == 1 {
+		return leafSum(v.hashF, v.version, v.elements[firstIndex]), true
+	}
+
+	half := size / 2
+	split := sort.SearchInts(indices, firstIndex+half)
+
+	leftHash, ok := v.nodeHash(firstIndex, half, indices[:split])
+	if !ok {
+		return nil, false
+	}
+	rightHash, ok := v.nodeHash(firstIndex+half, half, indices[split:])
+	if !ok {
+		return nil, false
+	}
+
+	return nodeSum(v.hashF, v.version, leftHash, rightHash), true
+}
+
+func sortedUniqueIndices(indices []int) []int {
+	sorted := make([]int, len(indices))
+	copy(sorted, indices)
+	sort.Ints(sorted)
+
+	unique := sorted[:0]
+	for _, index := range sorted {
+		if len(unique) == 0 || index != unique[len(unique)-1] {
+			unique = append(unique, index)
+		}
+	}
+	return unique
+}
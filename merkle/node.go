@@ -1,5 +1,7 @@
 package merkle
 
+import "sort"
+
 type Node struct {
 	hash []byte
 
@@ -28,3 +30,20 @@ func (n *Node) GetIndexProofs(i int) []Proof {
 
 	return proofs
 }
+
+// appends hashes of nodes needed to calculate this node hash from elements at sorted indices
+func (n *Node) collectMultiProof(indices []int, hashes [][]byte) [][]byte {
+
+	if len(indices) == 0 {
+		return append(hashes, n.hash)
+	}
+
+	// leaf hash is calculated from proven element
+	if n.left == nil {
+		return hashes
+	}
+
+	split := sort.SearchInts(indices, n.right.firstIndex)
+	hashes = n.left.collectMultiProof(indices[:split], hashes)
+	return n.right.collectMultiProof(indices[split:], hashes)
+}
@@ -0,0 +1,90 @@
+package merkle
+
+import (
+	"hash"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// chunks per worker, more chunks balance load better when workers are slowed down by others
+const chunksPerWorker = 4
+
+// Builds tree with data the same as pushing elements one by one, but using all CPUs.
+// Each subtree is split to chunks of power of 2 elements, chunks are built in parallel
+// with own hash.Hash per goroutine, then chunks roots are joined to subtrees roots.
+func NewTreeInParallel(newHash func() hash.Hash, full bool, version Version, data [][]byte) *Tree {
+
+	t := NewTree(newHash(), full, version)
+	if len(data) == 0 {
+		return t
+	}
+
+	workers := runtime.NumCPU()
+	chunkSize := 1
+	for chunkSize*workers*chunksPerWorker < len(data) {
+		chunkSize <<= 1
+	}
+
+	// chunks of each subtree from left to right, chunks roots are placed by chunk number
+	sizes := subtreesSizes(len(data))
+	chunksStarts := make([]int, 0)
+	subtreesChunks := make([]int, len(sizes)) // first chunk number of subtree
+	startIndex := 0
+	for i, size := range sizes {
+		subtreesChunks[i] = len(chunksStarts)
+		for start := startIndex; start < startIndex+size; start += chunkSize {
+			chunksStarts = append(chunksStarts, start)
+		}
+		startIndex += size
+	}
+	chunksRoots := make([]*Node, len(chunksStarts))
+
+	chunks := make(chan int, len(chunksStarts))
+	for chunk := range chunksStarts {
+		chunks <- chunk
+	}
+	close(chunks)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := newHash()
+			for chunk := range chunks {
+				// chunks go one by one, subtrees smaller than chunk size are built as one chunk
+				start, end := chunksStarts[chunk], len(data)
+				if chunk+1 < len(chunksStarts) {
+					end = chunksStarts[chunk+1]
+				}
+				chunksRoots[chunk] = buildSubTree(h, version, full, start, data[start:end]).root
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, size := range sizes {
+		lastChunk := len(chunksRoots)
+		if i+1 < len(sizes) {
+			lastChunk = subtreesChunks[i+1]
+		}
+
+		nextSubtree := &Subtree{
+			root:    sumNodes(t.hashF, version, full, chunksRoots[subtreesChunks[i]:lastChunk])[0],
+			height:  bits.TrailingZeros(uint(size)),
+			hashF:   t.hashF,
+			version: version,
+		}
+
+		if t.subTree != nil {
+			t.subTree.right = nextSubtree
+			nextSubtree.left = t.subTree
+		}
+		t.subTree = nextSubtree
+		t.subTreesCount++
+	}
+
+	t.lastIndex = len(data)
+	return t
+}
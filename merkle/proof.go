@@ -1,20 +1,162 @@
 package merkle
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"hash"
 )
 
+// version of proofs wire encoding, should be changed with any encoding change
+const proofsEncodingVersion = byte(1)
+
 type Proof struct {
 	LeftSide bool   `json:"leftSide"` // where proof should be placed to sum with hash (left or right side)
 	Hash     []byte `json:"hash"`
 }
 
 // calculate sum hash
-func (p *Proof) SumWith(hashF hash.Hash, hash []byte) []byte {
+func (p *Proof) SumWith(hashF hash.Hash, version Version, hash []byte) []byte {
 
 	if p.LeftSide {
-		return sum(hashF, p.Hash, hash)
+		return nodeSum(hashF, version, p.Hash, hash)
 	} else {
-		return sum(hashF, hash, p.Hash)
+		return nodeSum(hashF, version, hash, p.Hash)
+	}
+}
+
+// Verifies element at index by proofs against root hash of tree with given leaves count
+// without tree instance (e.g. app hash based light clients).
+func VerifyProof(
+	hashF hash.Hash, version Version, rootHash []byte, index int, leavesCount int, leaf []byte, proofs []Proof,
+) bool {
+
+	// element position is defined by proofs sides only
+	if !proofMatchesIndex(index, leavesCount, proofs) {
+		return false
+	}
+
+	hash := leafSum(hashF, version, leaf)
+	for _, proof := range proofs {
+		hash = proof.SumWith(hashF, version, hash)
+	}
+
+	return bytes.Equal(hash, rootHash)
+}
+
+// Checks proofs sides are the ones of element at index in tree with given elements count.
+func proofMatchesIndex(index int, leavesCount int, proofs []Proof) bool {
+
+	if index < 0 || index >= leavesCount {
+		return false
+	}
+
+	// subtrees from left to right, the biggest one first
+	firstIndex, leftSubtrees, height := 0, 0, 0
+	for h := 62; h >= 0; h-- {
+		size := 1 << uint(h)
+		if leavesCount&size == 0 {
+			continue
+		}
+		if index < firstIndex+size {
+			height = h
+			break
+		}
+		firstIndex += size
+		leftSubtrees++
+	}
+
+	sides := make([]bool, 0, len(proofs))
+	for h := 0; h < height; h++ {
+		sides = append(sides, (index>>uint(h))&1 == 1)
+	}
+	if firstIndex+(1<<uint(height)) < leavesCount {
+		sides = append(sides, true)
+	}
+	for i := 0; i < leftSubtrees; i++ {
+		sides = append(sides, false)
+	}
+
+	if len(sides) != len(proofs) {
+		return false
+	}
+	for i, proof := range proofs {
+		if proof.LeftSide != sides[i] {
+			return false
+		}
 	}
+	return true
+}
+
+// Stable wire encoding of proofs:
+// version byte | proofs count uvarint | for each proof: side byte (1 - left) | hash length uvarint | hash
+func EncodeProofs(proofs []Proof) []byte {
+
+	result := make([]byte, 0, 1+binary.MaxVarintLen64)
+	result = append(result, proofsEncodingVersion)
+	result = appendUvarint(result, uint64(len(proofs)))
+
+	for _, proof := range proofs {
+		side := byte(0)
+		if proof.LeftSide {
+			side = 1
+		}
+		result = append(result, side)
+		result = appendUvarint(result, uint64(len(proof.Hash)))
+		result = append(result, proof.Hash...)
+	}
+
+	return result
+}
+
+func DecodeProofs(data []byte) ([]Proof, error) {
+
+	if len(data) == 0 || data[0] != proofsEncodingVersion {
+		return nil, errors.New("unknown proofs encoding version")
+	}
+	reader := bytes.NewReader(data[1:])
+
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(reader.Len()) {
+		return nil, errors.New("proofs count exceeds data length")
+	}
+
+	proofs := make([]Proof, 0, count)
+	for i := uint64(0); i < count; i++ {
+		side, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if side > 1 {
+			return nil, errors.New("invalid proof side")
+		}
+
+		hashLen, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		if hashLen > uint64(reader.Len()) {
+			return nil, errors.New("proof hash length exceeds data length")
+		}
+
+		proofHash := make([]byte, hashLen)
+		if _, err := reader.Read(proofHash); err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, Proof{LeftSide: side == 1, Hash: proofHash})
+	}
+
+	if reader.Len() != 0 {
+		return nil, errors.New("unexpected data after proofs")
+	}
+	return proofs, nil
+}
+
+func appendUvarint(data []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, v)
+	return append(data, buf[:n]...)
 }
@@ -14,6 +14,8 @@ type Subtree struct {
 	height int // height of subtree
 	// hash function to hash sum nodes and hash data
 	hashF hash.Hash
+	// hashing version of tree this subtree belongs to
+	version Version
 }
 
 // get proofs for root of this subtree
@@ -55,7 +57,7 @@ func (t *Subtree) getRightSubtreesProof() []Proof {
 	n := len(hashesToSum) - 1
 	proofHash := hashesToSum[n]
 	for i := n - 1; i >= 0; i-- {
-		proofHash = sum(t.hashF, proofHash, hashesToSum[i])
+		proofHash = nodeSum(t.hashF, t.version, proofHash, hashesToSum[i])
 	}
 
 	return []Proof{{Hash: proofHash, LeftSide: true}}
@@ -1,7 +1,6 @@
 package merkle
 
 import (
-	"bytes"
 	"encoding/binary"
 	"hash"
 	"math"
@@ -25,10 +24,17 @@ type Tree struct {
 	// if false then store only roots of subtrees (no proofs available => suitable for consensus only)
 	// for 1,099,511,627,775 links tree would contain only 40 root hashes.
 	full bool
+
+	// elements and nodes hashing version, legacy one is kept to verify historical trees
+	version Version
+}
+
+func NewTree(hashF hash.Hash, full bool, version Version) *Tree {
+	return &Tree{hashF: hashF, full: full, version: version}
 }
 
-func NewTree(hashF hash.Hash, full bool) *Tree {
-	return &Tree{hashF: hashF, full: full}
+func (t *Tree) Version() Version {
+	return t.version
 }
 
 func (t *Tree) joinAllSubtrees() {
@@ -36,7 +42,7 @@ func (t *Tree) joinAllSubtrees() {
 	for t.subTree.left != nil && t.subTree.height == t.subTree.left.height {
 
 		newSubtreeRoot := &Node{
-			hash:       sum(t.hashF, t.subTree.left.root.hash, t.subTree.root.hash),
+			hash:       nodeSum(t.hashF, t.version, t.subTree.left.root.hash, t.subTree.root.hash),
 			firstIndex: t.subTree.left.root.firstIndex,
 			lastIndex:  t.subTree.root.lastIndex,
 		}
@@ -50,11 +56,12 @@ func (t *Tree) joinAllSubtrees() {
 		}
 
 		t.subTree = &Subtree{
-			root:   newSubtreeRoot,
-			right:  nil,
-			left:   t.subTree.left.left,
-			height: t.subTree.height + 1,
-			hashF:  t.hashF,
+			root:    newSubtreeRoot,
+			right:   nil,
+			left:    t.subTree.left.left,
+			height:  t.subTree.height + 1,
+			hashF:   t.hashF,
+			version: t.version,
 		}
 
 		if t.subTree.left != nil {
@@ -83,7 +90,7 @@ func (t *Tree) BuildNew(data [][]byte) {
 
 	for nextSubtreeLen != 0 {
 
-		nextSubtree := buildSubTree(t.hashF, t.full, int(startIndex), data[startIndex:endIndex])
+		nextSubtree := buildSubTree(t.hashF, t.version, t.full, int(startIndex), data[startIndex:endIndex])
 
 		if t.subTree != nil {
 			t.subTree.right = nextSubtree
@@ -109,7 +116,7 @@ func (t *Tree) BuildNew(data [][]byte) {
 func (t *Tree) Push(data []byte) {
 
 	newSubtreeRoot := &Node{
-		hash:       sum(t.hashF, data),
+		hash:       leafSum(t.hashF, t.version, data),
 		parent:     nil,
 		left:       nil,
 		right:      nil,
@@ -120,11 +127,12 @@ func (t *Tree) Push(data []byte) {
 	t.lastIndex++
 
 	t.subTree = &Subtree{
-		root:   newSubtreeRoot,
-		right:  nil,
-		left:   t.subTree,
-		height: 0,
-		hashF:  t.hashF,
+		root:    newSubtreeRoot,
+		right:   nil,
+		left:    t.subTree,
+		height:  0,
+		hashF:   t.hashF,
+		version: t.version,
 	}
 
 	if t.subTree.left != nil {
@@ -167,13 +175,7 @@ func (t *Tree) ValidateIndex(i int, data []byte) bool {
 }
 
 func (t *Tree) ValidateIndexByProofs(i int, data []byte, proofs []Proof) bool {
-
-	rootHash := sum(t.hashF, data)
-	for _, proof := range proofs {
-		rootHash = proof.SumWith(t.hashF, rootHash)
-	}
-
-	return bytes.Equal(rootHash, t.RootHash())
+	return VerifyProof(t.hashF, t.version, t.RootHash(), i, t.LeavesCount(), data, proofs)
 }
 
 // root hash calculates from right to left by summing subtrees root hashes.
@@ -187,7 +189,7 @@ func (t *Tree) RootHash() []byte {
 	current := t.subTree.left
 
 	for current != nil {
-		rootHash = sum(t.hashF, rootHash, current.root.hash)
+		rootHash = nodeSum(t.hashF, t.version, rootHash, current.root.hash)
 		current = current.left
 	}
 
@@ -219,8 +221,14 @@ func (t *Tree) ExportSubtreesRoots() []byte {
 	return result
 }
 
+// elements count of this tree
+func (t *Tree) LeavesCount() int {
+	return t.lastIndex
+}
+
 // from right to left
 // after import we loosing indices (actually they don't need for consensus and pushing)
+// only elements count is restored from subtrees heights
 func (t *Tree) ImportSubtreesRoots(subTreesRoots []byte) {
 	t.Reset()
 	t.full = false
@@ -243,6 +251,7 @@ func (t *Tree) ImportSubtreesRoots(subTreesRoots []byte) {
 			},
 			height: int(height),
 		}
+		t.lastIndex += 1 << height
 
 		if current != nil {
 			nextSubtree.right = current
@@ -1,6 +1,7 @@
 package merkle
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
 	"github.com/stretchr/testify/require"
@@ -9,7 +10,7 @@ import (
 
 func TestPushAndProofs(t *testing.T) {
 
-	tree := NewTree(sha256.New(), true)
+	tree := NewTree(sha256.New(), true, LegacyVersion)
 
 	data := make([]byte, 8)
 
@@ -29,7 +30,7 @@ func TestPushAndProofs(t *testing.T) {
 
 func TestBuildNewAndProofs(t *testing.T) {
 
-	tree := NewTree(sha256.New(), true)
+	tree := NewTree(sha256.New(), true, LegacyVersion)
 
 	allData := make([][]byte, 0, 31)
 
@@ -53,7 +54,7 @@ func TestBuildNewAndProofs(t *testing.T) {
 
 func TestEqualityOfBuildNewAndPush(t *testing.T) {
 
-	tree1 := NewTree(sha256.New(), true)
+	tree1 := NewTree(sha256.New(), true, LegacyVersion)
 
 	data := make([]byte, 8)
 
@@ -62,7 +63,7 @@ func TestEqualityOfBuildNewAndPush(t *testing.T) {
 		tree1.Push(data)
 	}
 
-	tree2 := NewTree(sha256.New(), true)
+	tree2 := NewTree(sha256.New(), true, LegacyVersion)
 
 	allData := make([][]byte, 0, 31)
 
@@ -77,8 +78,100 @@ func TestEqualityOfBuildNewAndPush(t *testing.T) {
 	require.Equal(t, tree1.RootHash(), tree2.RootHash())
 }
 
+func TestEqualityOfBuildInParallelAndPush(t *testing.T) {
+
+	for _, version := range []Version{LegacyVersion, DomainSeparatedVersion} {
+		for _, size := range []int{0, 1, 2, 3, 31, 32, 33, 100, 1000, 1023} {
+
+			pushed := NewTree(sha256.New(), true, version)
+			allData := make([][]byte, 0, size)
+			for i := 0; i < size; i++ {
+				data := make([]byte, 8)
+				binary.LittleEndian.PutUint64(data, uint64(i))
+				allData = append(allData, data)
+				pushed.Push(data)
+			}
+
+			full := NewTreeInParallel(sha256.New, true, version, allData)
+			require.Equal(t, pushed.RootHash(), full.RootHash())
+			require.Equal(t, pushed.ExportSubtreesRoots(), full.ExportSubtreesRoots())
+			require.Equal(t, size, full.LeavesCount())
+
+			for i := 0; i < size; i++ {
+				proofs := full.GetIndexProofs(i)
+				require.Equal(t, pushed.GetIndexProofs(i), proofs)
+				require.Equal(t, true, full.ValidateIndexByProofs(i, allData[i], proofs))
+			}
+
+			notFull := NewTreeInParallel(sha256.New, false, version, allData)
+			require.Equal(t, pushed.RootHash(), notFull.RootHash())
+			require.Nil(t, notFull.GetIndexProofs(0))
+
+			// tree built in parallel could be extended as usual one
+			data := []byte{1}
+			pushed.Push(data)
+			full.Push(data)
+			require.Equal(t, pushed.RootHash(), full.RootHash())
+		}
+	}
+}
+
+func TestFlatTree(t *testing.T) {
+
+	for _, size := range []int{0, 1, 2, 3, 31, 32, 33, 100} {
+
+		tree := NewTree(sha256.New(), true, DomainSeparatedVersion)
+		allData := make([][]byte, 0, size)
+		for i := 0; i < size; i++ {
+			data := make([]byte, 8)
+			binary.LittleEndian.PutUint64(data, uint64(i))
+			allData = append(allData, data)
+			tree.Push(data)
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteFlatTree(&buf, sha256.New(), DomainSeparatedVersion, allData))
+
+		flat, err := NewFlatTree(bytes.NewReader(buf.Bytes()), int64(buf.Len()), sha256.New())
+		require.NoError(t, err)
+		require.Equal(t, DomainSeparatedVersion, flat.Version())
+
+		// pushed elements are kept in memory and extend stored nodes
+		for pushed := 0; pushed < 5; pushed++ {
+			require.Equal(t, tree.LeavesCount(), flat.LeavesCount())
+
+			rootHash, err := flat.RootHash()
+			require.NoError(t, err)
+			require.Equal(t, tree.RootHash(), rootHash)
+
+			for i := 0; i < tree.LeavesCount(); i++ {
+				proofs, err := flat.GetIndexProofs(i)
+				require.NoError(t, err)
+				require.Equal(t, tree.GetIndexProofs(i), proofs)
+			}
+
+			if tree.LeavesCount() != 0 {
+				indices := []int{tree.LeavesCount() / 2, 0, tree.LeavesCount() - 1}
+				multiProof, err := flat.GetMultiProof(indices)
+				require.NoError(t, err)
+				require.Equal(t, tree.GetMultiProof(indices), multiProof)
+			}
+
+			data := []byte{byte(pushed)}
+			tree.Push(data)
+			require.NoError(t, flat.Push(data))
+		}
+
+		_, err = flat.GetIndexProofs(flat.LeavesCount())
+		require.Error(t, err)
+	}
+
+	_, err := NewFlatTree(bytes.NewReader([]byte{flatTreeFormat, 1, 1, 0, 0, 0, 0, 0, 0, 0}), 10, sha256.New())
+	require.Error(t, err)
+}
+
 func TestNotFull(t *testing.T) {
-	tree1 := NewTree(sha256.New(), true)
+	tree1 := NewTree(sha256.New(), true, LegacyVersion)
 
 	data := make([]byte, 8)
 
@@ -87,14 +180,14 @@ func TestNotFull(t *testing.T) {
 		tree1.Push(data)
 	}
 
-	tree2 := NewTree(sha256.New(), false)
+	tree2 := NewTree(sha256.New(), false, LegacyVersion)
 
 	for i := 0; i < 31; i++ {
 		binary.LittleEndian.PutUint64(data, uint64(i))
 		tree2.Push(data)
 	}
 
-	tree3 := NewTree(sha256.New(), false)
+	tree3 := NewTree(sha256.New(), false, LegacyVersion)
 
 	allData := make([][]byte, 0, 31)
 
@@ -111,7 +204,7 @@ func TestNotFull(t *testing.T) {
 }
 
 func TestExportImport(t *testing.T) {
-	tree1 := NewTree(sha256.New(), true)
+	tree1 := NewTree(sha256.New(), true, LegacyVersion)
 
 	data := make([]byte, 8)
 
@@ -122,13 +215,247 @@ func TestExportImport(t *testing.T) {
 
 	subtreeRoots := tree1.ExportSubtreesRoots()
 
-	tree2 := NewTree(sha256.New(), false)
+	tree2 := NewTree(sha256.New(), false, LegacyVersion)
 	tree2.ImportSubtreesRoots(subtreeRoots)
 
 	require.Equal(t, tree1.RootHash(), tree2.RootHash())
+	require.Equal(t, 31, tree2.LeavesCount())
 
 	binary.LittleEndian.PutUint64(data, uint64(31))
 	tree1.Push(data)
 	tree2.Push(data)
 	require.Equal(t, tree1.RootHash(), tree2.RootHash())
+	require.Equal(t, 32, tree2.LeavesCount())
+}
+
+func TestMultiProof(t *testing.T) {
+
+	tree := NewTree(sha256.New(), true, LegacyVersion)
+
+	allData := make([][]byte, 0, 31)
+
+	for i := 0; i < 31; i++ {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		allData = append(allData, data)
+	}
+
+	tree.BuildNew(allData)
+
+	for _, indices := range [][]int{{0}, {30}, {3, 4, 5}, {29, 0, 16, 17, 16}, {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30}} {
+		proof := tree.GetMultiProof(indices)
+		require.NotNil(t, proof)
+
+		data := make([][]byte, 0, len(indices))
+		for _, i := range indices {
+			data = append(data, allData[i])
+		}
+		require.Equal(t, true, VerifyMultiProof(sha256.New(), LegacyVersion, tree.RootHash(), *proof, data))
+
+		// any other data should fail verification
+		data[0] = allData[(indices[0]+1)%31]
+		require.Equal(t, false, VerifyMultiProof(sha256.New(), LegacyVersion, tree.RootHash(), *proof, data))
+	}
+
+	// shared nodes are included once
+	require.Len(t, tree.GetMultiProof([]int{0, 1, 2, 3}).Hashes, 3)
+	require.Len(t, tree.GetMultiProof(make([]int, 0)).Hashes, 1)
+
+	require.Nil(t, tree.GetMultiProof([]int{31}))
+	require.Nil(t, NewTree(sha256.New(), false, LegacyVersion).GetMultiProof([]int{0}))
+}
+
+func TestVerifyProofWithoutTree(t *testing.T) {
+
+	tree := NewTree(sha256.New(), true, LegacyVersion)
+
+	data := make([]byte, 8)
+
+	for i := 0; i < 31; i++ {
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		tree.Push(data)
+	}
+
+	rootHash := tree.RootHash()
+
+	for i := 0; i < 31; i++ {
+		proofs, err := DecodeProofs(EncodeProofs(tree.GetIndexProofs(i)))
+		require.NoError(t, err)
+		require.Equal(t, tree.GetIndexProofs(i), proofs)
+
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		require.Equal(t, true, VerifyProof(sha256.New(), LegacyVersion, rootHash, i, 31, data, proofs))
+
+		// proof is bound to index only with tree size
+		for j := 0; j < 31; j++ {
+			require.Equal(t, i == j, proofMatchesIndex(j, 31, proofs))
+		}
+		require.Equal(t, false, VerifyProof(sha256.New(), LegacyVersion, rootHash, i, 32, data, proofs))
+	}
+
+	// right leaf of pair with left leaf index
+	binary.LittleEndian.PutUint64(data, uint64(5))
+	proofs := tree.GetIndexProofs(5)
+	require.Equal(t, true, VerifyProof(sha256.New(), LegacyVersion, rootHash, 5, 31, data, proofs))
+	require.Equal(t, false, VerifyProof(sha256.New(), LegacyVersion, rootHash, 4, 31, data, proofs))
+	require.Equal(t, false, VerifyProof(sha256.New(), LegacyVersion, rootHash, -1, 31, data, proofs))
+
+	_, err := DecodeProofs([]byte{0})
+	require.Error(t, err)
 }
+
+func TestConsistencyProof(t *testing.T) {
+
+	tree := NewTree(sha256.New(), true, LegacyVersion)
+	rootHashes := make([][]byte, 0, 32)
+
+	data := make([]byte, 8)
+
+	for i := 0; i < 31; i++ {
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		tree.Push(data)
+		rootHashes = append(rootHashes, tree.RootHash())
+	}
+
+	for oldSize := 1; oldSize <= 31; oldSize++ {
+		for newSize := oldSize; newSize <= 31; newSize++ {
+			proof := tree.GetConsistencyProof(oldSize, newSize)
+			require.NotNil(t, proof)
+			require.Equal(t, true, VerifyConsistencyProof(sha256.New(), LegacyVersion, rootHashes[oldSize-1], rootHashes[newSize-1], *proof))
+
+			if oldSize > 1 {
+				// rewritten old tree
+				require.Equal(t, false, VerifyConsistencyProof(sha256.New(), LegacyVersion, rootHashes[oldSize-2], rootHashes[newSize-1], *proof))
+			}
+		}
+	}
+
+	require.Nil(t, tree.GetConsistencyProof(0, 31))
+	require.Nil(t, tree.GetConsistencyProof(5, 32))
+	require.Nil(t, tree.GetConsistencyProof(6, 5))
+}
+
+func TestDomainSeparatedVersion(t *testing.T) {
+
+	legacyTree := NewTree(sha256.New(), true, LegacyVersion)
+	tree := NewTree(sha256.New(), true, DomainSeparatedVersion)
+
+	allData := make([][]byte, 0, 31)
+
+	for i := 0; i < 31; i++ {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		allData = append(allData, data)
+		legacyTree.Push(data)
+	}
+
+	tree.BuildNew(allData)
+	require.NotEqual(t, legacyTree.RootHash(), tree.RootHash())
+
+	for i := 0; i < 31; i++ {
+		proofs := tree.GetIndexProofs(i)
+		require.Equal(t, true, tree.ValidateIndexByProofs(i, allData[i], proofs))
+		require.Equal(t, false, VerifyProof(sha256.New(), LegacyVersion, tree.RootHash(), i, 31, allData[i], proofs))
+	}
+
+	proof := tree.GetMultiProof([]int{1, 7, 30})
+	data := [][]byte{allData[1], allData[7], allData[30]}
+	require.Equal(t, true, VerifyMultiProof(sha256.New(), DomainSeparatedVersion, tree.RootHash(), *proof, data))
+	require.Equal(t, false, VerifyMultiProof(sha256.New(), LegacyVersion, tree.RootHash(), *proof, data))
+
+	imported := NewTree(sha256.New(), false, DomainSeparatedVersion)
+	imported.ImportSubtreesRoots(tree.ExportSubtreesRoots())
+	require.Equal(t, tree.RootHash(), imported.RootHash())
+}
+
+func TestUpdate(t *testing.T) {
+
+	allData := make([][]byte, 0, 31)
+	for i := 0; i < 31; i++ {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		allData = append(allData, data)
+	}
+
+	tree := NewTree(sha256.New(), true, DomainSeparatedVersion)
+	tree.BuildNew(allData)
+
+	updated := make([][]byte, 31)
+	copy(updated, allData)
+	indices := []int{0, 5, 16, 29, 30}
+	data := make([][]byte, 0, len(indices))
+	for _, i := range indices {
+		updated[i] = []byte{byte(i), 0xff}
+		data = append(data, updated[i])
+	}
+
+	require.Equal(t, true, tree.UpdateBulk(indices, data))
+	require.Equal(t, true, tree.Update(7, []byte{0x07}))
+	updated[7] = []byte{0x07}
+
+	expected := NewTree(sha256.New(), true, DomainSeparatedVersion)
+	expected.BuildNew(updated)
+	require.Equal(t, expected.RootHash(), tree.RootHash())
+
+	for i := 0; i < 31; i++ {
+		require.Equal(t, true, tree.ValidateIndexByProofs(i, updated[i], tree.GetIndexProofs(i)))
+	}
+
+	require.Equal(t, false, tree.Update(31, allData[0]))
+	notFull := NewTree(sha256.New(), false, DomainSeparatedVersion)
+	notFull.BuildNew(allData)
+	require.Equal(t, false, notFull.Update(0, allData[1]))
+}
+
+const benchmarkTreeSize = 1 << 20
+
+func benchmarkTreeData() [][]byte {
+	allData := make([][]byte, 0, benchmarkTreeSize)
+	for i := 0; i < benchmarkTreeSize; i++ {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, uint64(i))
+		allData = append(allData, data)
+	}
+	return allData
+}
+
+func BenchmarkRebuildTree(b *testing.B) {
+	allData := benchmarkTreeData()
+	tree := NewTree(sha256.New(), true, DomainSeparatedVersion)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree.BuildNew(allData)
+	}
+}
+
+func BenchmarkBuildTreeInParallel(b *testing.B) {
+	allData := benchmarkTreeData()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		NewTreeInParallel(sha256.New, true, DomainSeparatedVersion, allData)
+	}
+}
+
+func benchmarkUpdateTree(b *testing.B, changedShare int) {
+	allData := benchmarkTreeData()
+	tree := NewTree(sha256.New(), true, DomainSeparatedVersion)
+	tree.BuildNew(allData)
+
+	indices := make([]int, 0, benchmarkTreeSize/changedShare)
+	data := make([][]byte, 0, benchmarkTreeSize/changedShare)
+	for i := 0; i < benchmarkTreeSize; i += changedShare {
+		indices = append(indices, i)
+		data = append(data, []byte{byte(i)})
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree.UpdateBulk(indices, data)
+	}
+}
+
+func BenchmarkUpdateTreeOnePercent(b *testing.B)  { benchmarkUpdateTree(b, 100) }
+func BenchmarkUpdateTreeTenPercent(b *testing.B)  { benchmarkUpdateTree(b, 10) }
+func BenchmarkUpdateTreeHalfChanged(b *testing.B) { benchmarkUpdateTree(b, 2) }
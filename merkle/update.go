@@ -0,0 +1,63 @@
+package merkle
+
+import "sort"
+
+// Replaces element at index and rehashes only nodes on path to subtree root.
+// Returns false for not full tree (no elements nodes) or index out of tree.
+func (t *Tree) Update(index int, data []byte) bool {
+	return t.UpdateBulk([]int{index}, [][]byte{data})
+}
+
+// Replaces elements at indices with data (data[i] is new element at indices[i]).
+// Each changed node is rehashed once, so it's cheaper than rebuilding tree if only part of elements changed.
+// Returns false for not full tree or any index out of tree, tree is not modified then.
+func (t *Tree) UpdateBulk(indices []int, data [][]byte) bool {
+
+	// we cannot update not full tree cause there are no elements nodes
+	if !t.full || len(indices) != len(data) {
+		return false
+	}
+	for _, i := range indices {
+		if i < 0 || i >= t.lastIndex {
+			return false
+		}
+	}
+
+	// nodes of the same level have children on previous level, so going level by level
+	// we rehash each node once after all its changed children.
+	// Nodes of level are sorted by elements indices, so parents of neighbour nodes are neighbours too
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return indices[order[i]] < indices[order[j]] })
+
+	changed := make([]*Node, 0, len(indices))
+	for _, i := range order {
+		leaf := t.findNode(indices[i], 1)
+		leaf.hash = leafSum(t.hashF, t.version, data[i])
+		changed = appendParent(changed, leaf)
+	}
+
+	for len(changed) != 0 {
+		nextChanged := make([]*Node, 0, len(changed))
+		for _, node := range changed {
+			node.hash = nodeSum(t.hashF, t.version, node.left.hash, node.right.hash)
+			nextChanged = appendParent(nextChanged, node)
+		}
+		changed = nextChanged
+	}
+
+	return true
+}
+
+func (t *Tree) IsFull() bool {
+	return t.full
+}
+
+func appendParent(nodes []*Node, node *Node) []*Node {
+	if node.parent == nil || (len(nodes) != 0 && nodes[len(nodes)-1] == node.parent) {
+		return nodes
+	}
+	return append(nodes, node.parent)
+}
@@ -16,31 +16,32 @@ func sum(h hash.Hash, data ...[]byte) []byte {
 
 // number of data elements should be power of 2
 // not suitable for parallel calculations cause using same hash.Hash
-func buildSubTree(h hash.Hash, full bool, startIndex int, data [][]byte) *Subtree {
+func buildSubTree(h hash.Hash, version Version, full bool, startIndex int, data [][]byte) *Subtree {
 
 	nodes := make([]*Node, len(data))
 	for i := 0; i < len(data); i++ {
 
 		nodes[i] = &Node{
-			hash:       sum(h, data[i]),
+			hash:       leafSum(h, version, data[i]),
 			firstIndex: startIndex + i,
 			lastIndex:  startIndex + i,
 		}
 
 	}
 
-	root := sumNodes(h, full, nodes)[0]
+	root := sumNodes(h, version, full, nodes)[0]
 
 	return &Subtree{
-		root:   root,
-		left:   nil,
-		right:  nil,
-		height: int(math.Log2(float64(len(data)))),
-		hashF:  h,
+		root:    root,
+		left:    nil,
+		right:   nil,
+		height:  int(math.Log2(float64(len(data)))),
+		hashF:   h,
+		version: version,
 	}
 }
 
-func sumNodes(h hash.Hash, full bool, nodes []*Node) []*Node {
+func sumNodes(h hash.Hash, version Version, full bool, nodes []*Node) []*Node {
 
 	if len(nodes) == 1 {
 		return nodes
@@ -48,17 +49,17 @@ func sumNodes(h hash.Hash, full bool, nodes []*Node) []*Node {
 
 	newNodes := make([]*Node, len(nodes)/2)
 	for i := 0; i < len(nodes); i += 2 {
-		newNodes[i/2] = joinNodes(h, full, nodes[i], nodes[i+1])
+		newNodes[i/2] = joinNodes(h, version, full, nodes[i], nodes[i+1])
 	}
 
-	return sumNodes(h, full, newNodes)
+	return sumNodes(h, version, full, newNodes)
 }
 
-func joinNodes(h hash.Hash, full bool, left *Node, right *Node) *Node {
+func joinNodes(h hash.Hash, version Version, full bool, left *Node, right *Node) *Node {
 	newNode := &Node{
 		firstIndex: left.firstIndex,
 		lastIndex:  right.lastIndex,
-		hash:       sum(h, left.hash, right.hash),
+		hash:       nodeSum(h, version, left.hash, right.hash),
 	}
 
 	if full {
@@ -69,3 +70,14 @@ func joinNodes(h hash.Hash, full bool, left *Node, right *Node) *Node {
 	}
 	return newNode
 }
+
+// subtrees sizes of tree with given elements count from the biggest one (leftmost)
+func subtreesSizes(leavesCount int) []int {
+	sizes := make([]int, 0)
+	for size := 1; size > 0 && size <= leavesCount; size <<= 1 {
+		if leavesCount&size != 0 {
+			sizes = append([]int{size}, sizes...)
+		}
+	}
+	return sizes
+}
@@ -0,0 +1,38 @@
+package merkle
+
+import "hash"
+
+// Version defines how tree elements and nodes are hashed.
+// Trees with different versions have different root hashes for the same elements.
+type Version uint8
+
+const (
+	// elements hashed as H(data), nodes as H(left||right)
+	LegacyVersion Version = 0
+	// RFC-6962 domain separation: elements hashed as H(0x00||data), nodes as H(0x01||left||right),
+	// so element hash couldn't be confused with node hash (second-preimage attack)
+	DomainSeparatedVersion Version = 1
+)
+
+var (
+	leafHashPrefix = []byte{0x00}
+	nodeHashPrefix = []byte{0x01}
+)
+
+func (v Version) IsValid() bool {
+	return v == LegacyVersion || v == DomainSeparatedVersion
+}
+
+func leafSum(h hash.Hash, version Version, data []byte) []byte {
+	if version == DomainSeparatedVersion {
+		return sum(h, leafHashPrefix, data)
+	}
+	return sum(h, data)
+}
+
+func nodeSum(h hash.Hash, version Version, left []byte, right []byte) []byte {
+	if version == DomainSeparatedVersion {
+		return sum(h, nodeHashPrefix, left, right)
+	}
+	return sum(h, left, right)
+}
@@ -4,12 +4,16 @@ import (
 	"encoding/binary"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"math"
+
+	"github.com/cybercongress/go-cyber/merkle"
 )
 
 var lastCidNumberKey = []byte("cyberd_last_cid_number")
 var linksCountKey = []byte("cyberd_links_count")
 var genesisSupplyKey = []byte("cyberd_genesis_supply")
 var lastBandwidthPrice = []byte("cyberd_last_bandwidth_price")
+var lastBandwidthPriceDec = []byte("cyberd_last_bandwidth_price_dec")
+var bandwidthPriceHistoryPrefix = []byte("cyberd_bandwidth_price_history")
 var spentBandwidth = []byte("cyberd_spent_bandwidth")
 var spentKarma = []byte("cyberd_latest_karma")
 var latestBlockNumber = []byte("cyberd_latest_block_number")
@@ -17,6 +21,11 @@ var latestMerkleTree = []byte("cyberd_latest_merkle_tree")
 var nextMerkleTree = []byte("cyberd_next_merkle_tree")
 var rankCalculationFinished = []byte("cyberd_rank_calc_finished")
 var nextRankCidCount = []byte("cyberd_next_rank_cid_count")
+var rankTreeVersion = []byte("cyberd_rank_tree_version")
+var latestMerkleTreeVersion = []byte("cyberd_latest_merkle_tree_version")
+var nextMerkleTreeVersion = []byte("cyberd_next_merkle_tree_version")
+var latestRankCalcHeight = []byte("cyberd_latest_rank_calc_height")
+var nextRankCalcHeight = []byte("cyberd_next_rank_calc_height")
 
 type MainKeeper struct {
 	storeKey sdk.StoreKey
@@ -94,6 +103,92 @@ func (ms MainKeeper) StoreBandwidthPrice(ctx sdk.Context, price uint64) {
 	store.Set(lastBandwidthPrice, priceAsBytes)
 }
 
+// price stored as decimal after bandwidth fixed point math upgrade
+func (ms MainKeeper) GetBandwidthPriceDec(ctx sdk.Context, basePrice sdk.Dec) sdk.Dec {
+	store := ctx.KVStore(ms.storeKey)
+	priceAsBytes := store.Get(lastBandwidthPriceDec)
+	if priceAsBytes == nil {
+		return basePrice
+	}
+	var price sdk.Dec
+	if err := price.UnmarshalAmino(string(priceAsBytes)); err != nil {
+		panic(err)
+	}
+	return price
+}
+
+func (ms MainKeeper) StoreBandwidthPriceDec(ctx sdk.Context, price sdk.Dec) {
+	priceAsString, err := price.MarshalAmino()
+	if err != nil {
+		panic(err)
+	}
+	ctx.KVStore(ms.storeKey).Set(lastBandwidthPriceDec, []byte(priceAsString))
+}
+
+// key of price history record, big endian height keeps records ordered by height
+func bandwidthPriceHistoryKey(height int64) []byte {
+	key := make([]byte, len(bandwidthPriceHistoryPrefix)+8)
+	copy(key, bandwidthPriceHistoryPrefix)
+	binary.BigEndian.PutUint64(key[len(bandwidthPriceHistoryPrefix):], uint64(height))
+	return key
+}
+
+// stores price and network load set by price adjustment at given height
+func (ms MainKeeper) StoreBandwidthPriceRecord(ctx sdk.Context, height int64, price uint64, load uint64) {
+	store := ctx.KVStore(ms.storeKey)
+	recordAsBytes := make([]byte, 16)
+	binary.LittleEndian.PutUint64(recordAsBytes[:8], price)
+	binary.LittleEndian.PutUint64(recordAsBytes[8:], load)
+	store.Set(bandwidthPriceHistoryKey(height), recordAsBytes)
+}
+
+// iterates price records with heights in [fromHeight, toHeight] by height ascending until cb returns true
+func (ms MainKeeper) IterateBandwidthPriceRecords(
+	ctx sdk.Context, fromHeight int64, toHeight int64, cb func(height int64, price uint64, load uint64) (stop bool),
+) {
+	if fromHeight < 0 {
+		fromHeight = 0
+	}
+	if toHeight < fromHeight {
+		return
+	}
+
+	store := ctx.KVStore(ms.storeKey)
+	end := sdk.PrefixEndBytes(bandwidthPriceHistoryPrefix)
+	if toHeight < math.MaxInt64 {
+		end = bandwidthPriceHistoryKey(toHeight + 1)
+	}
+	iterator := store.Iterator(bandwidthPriceHistoryKey(fromHeight), end)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		height := int64(binary.BigEndian.Uint64(iterator.Key()[len(bandwidthPriceHistoryPrefix):]))
+		value := iterator.Value()
+		if cb(height, binary.LittleEndian.Uint64(value[:8]), binary.LittleEndian.Uint64(value[8:])) {
+			break
+		}
+	}
+}
+
+// removes price records with heights lower than given one
+func (ms MainKeeper) PruneBandwidthPriceRecords(ctx sdk.Context, beforeHeight int64) {
+	if beforeHeight <= 0 {
+		return
+	}
+
+	store := ctx.KVStore(ms.storeKey)
+	iterator := store.Iterator(bandwidthPriceHistoryKey(0), bandwidthPriceHistoryKey(beforeHeight))
+	keys := make([][]byte, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
 func (ms MainKeeper) GetSpentBandwidth(ctx sdk.Context) uint64 {
 	store := ctx.KVStore(ms.storeKey)
 	bandwidthAsBytes := store.Get(spentBandwidth)
@@ -162,6 +257,46 @@ func (ms MainKeeper) StoreNextMerkleTree(ctx sdk.Context, treeAsBytes []byte) {
 	store.Set(nextMerkleTree, treeAsBytes)
 }
 
+// versions are stored only after upgrade to not legacy version, absent version is legacy one
+func (ms MainKeeper) getTreeVersion(ctx sdk.Context, key []byte) merkle.Version {
+	store := ctx.KVStore(ms.storeKey)
+	versionAsBytes := store.Get(key)
+	if versionAsBytes == nil {
+		return merkle.LegacyVersion
+	}
+	return merkle.Version(versionAsBytes[0])
+}
+
+func (ms MainKeeper) storeTreeVersion(ctx sdk.Context, key []byte, version merkle.Version) {
+	store := ctx.KVStore(ms.storeKey)
+	store.Set(key, []byte{byte(version)})
+}
+
+// version of merkle trees of rank calculations to be started
+func (ms MainKeeper) GetRankTreeVersion(ctx sdk.Context) merkle.Version {
+	return ms.getTreeVersion(ctx, rankTreeVersion)
+}
+
+func (ms MainKeeper) StoreRankTreeVersion(ctx sdk.Context, version merkle.Version) {
+	ms.storeTreeVersion(ctx, rankTreeVersion, version)
+}
+
+func (ms MainKeeper) GetLatestMerkleTreeVersion(ctx sdk.Context) merkle.Version {
+	return ms.getTreeVersion(ctx, latestMerkleTreeVersion)
+}
+
+func (ms MainKeeper) StoreLatestMerkleTreeVersion(ctx sdk.Context, version merkle.Version) {
+	ms.storeTreeVersion(ctx, latestMerkleTreeVersion, version)
+}
+
+func (ms MainKeeper) GetNextMerkleTreeVersion(ctx sdk.Context) merkle.Version {
+	return ms.getTreeVersion(ctx, nextMerkleTreeVersion)
+}
+
+func (ms MainKeeper) StoreNextMerkleTreeVersion(ctx sdk.Context, version merkle.Version) {
+	ms.storeTreeVersion(ctx, nextMerkleTreeVersion, version)
+}
+
 func (ms MainKeeper) StoreRankCalculationFinished(ctx sdk.Context, finished bool) {
 	store := ctx.KVStore(ms.storeKey)
 	var byteFlag byte
@@ -195,3 +330,36 @@ func (ms MainKeeper) StoreNextRankCidCount(ctx sdk.Context, number uint64) {
 	binary.LittleEndian.PutUint64(numberAsBytes, number)
 	store.Set(nextRankCidCount, numberAsBytes)
 }
+
+// returns height network rank calculation was started at, 0 if rank was applied before it was stored
+func (ms MainKeeper) GetLatestRankCalcHeight(ctx sdk.Context) int64 {
+	return ms.getHeight(ctx, latestRankCalcHeight)
+}
+
+func (ms MainKeeper) StoreLatestRankCalcHeight(ctx sdk.Context, height int64) {
+	ms.storeHeight(ctx, latestRankCalcHeight, height)
+}
+
+func (ms MainKeeper) GetNextRankCalcHeight(ctx sdk.Context) int64 {
+	return ms.getHeight(ctx, nextRankCalcHeight)
+}
+
+func (ms MainKeeper) StoreNextRankCalcHeight(ctx sdk.Context, height int64) {
+	ms.storeHeight(ctx, nextRankCalcHeight, height)
+}
+
+func (ms MainKeeper) getHeight(ctx sdk.Context, key []byte) int64 {
+	store := ctx.KVStore(ms.storeKey)
+	heightAsBytes := store.Get(key)
+	if heightAsBytes == nil {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(heightAsBytes))
+}
+
+func (ms MainKeeper) storeHeight(ctx sdk.Context, key []byte, height int64) {
+	store := ctx.KVStore(ms.storeKey)
+	heightAsBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heightAsBytes, uint64(height))
+	store.Set(key, heightAsBytes)
+}
@@ -11,7 +11,9 @@ var accountsToUpdate = make([]sdk.AccAddress, 0)
 // user to recover and update bandwidth for accounts with changed stake
 func updateAccMaxBandwidth(ctx sdk.Context, meter types.BandwidthMeter) {
 	for _, addr := range accountsToUpdate {
-		meter.UpdateAccMaxBandwidth(ctx, addr)
+		if err := meter.UpdateAccMaxBandwidth(ctx, addr); err != nil {
+			ctx.Logger().Error("failed to update account max bandwidth", "address", addr, "err", err)
+		}
 	}
 	accountsToUpdate = make([]sdk.AccAddress, 0)
 }
@@ -9,6 +9,7 @@ const (
 	ModuleName        		= types.ModuleName
 	DefaultParamspace 		= types.DefaultParamspace
 	StoreKey          		= types.StoreKey
+	RouterKey               = types.RouterKey
 	QuerierRoute            = types.QuerierRoute
 	QueryParameters         = types.QueryParameters
 	QueryDesirableBandwidth = types.QueryDesirableBandwidth
@@ -19,6 +20,45 @@ const (
 	QueryTxCost             = types.QueryTxCost
 	QueryLinkMsgCost        = types.QueryLinkMsgCost
 	QueryNonLinkMsgCost     = types.QueryNonLinkMsgCost
+	QueryAccountBandwidth   = types.QueryAccountBandwidth
+	QueryPrice              = types.QueryPrice
+	QueryLoad               = types.QueryLoad
+	QueryPriceHistory       = types.QueryPriceHistory
+	QueryMsgCosts           = types.QueryMsgCosts
+	QueryGrants             = types.QueryGrants
+	BlockSpentPruningUpgrade = types.BlockSpentPruningUpgrade
+	AccountBandwidthEncodingUpgrade = types.AccountBandwidthEncodingUpgrade
+	FixedPointMathUpgrade           = types.FixedPointMathUpgrade
+	SponsoredBandwidthUpgrade       = types.SponsoredBandwidthUpgrade
+	MaxGrantsPerGranter     = types.MaxGrantsPerGranter
+	MaxGrantsPerGrantee     = types.MaxGrantsPerGrantee
+	MaxWhitelistedAccounts  = types.MaxWhitelistedAccounts
+	MaxMsgCost              = types.MaxMsgCost
+	EventTypeGrantBandwidth  = types.EventTypeGrantBandwidth
+	EventTypeRevokeBandwidth = types.EventTypeRevokeBandwidth
+	EventTypeBandwidth       = types.EventTypeBandwidth
+	EventTypeKarma           = types.EventTypeKarma
+	EventTypeBandwidthPrice  = types.EventTypeBandwidthPrice
+	EventTypeMempoolBandwidth = types.EventTypeMempoolBandwidth
+	AttributeKeyGranter      = types.AttributeKeyGranter
+	AttributeKeyGrantee      = types.AttributeKeyGrantee
+	AttributeKeyFraction     = types.AttributeKeyFraction
+	AttributeKeyExpiryHeight = types.AttributeKeyExpiryHeight
+	AttributeKeyAccount      = types.AttributeKeyAccount
+	AttributeKeyCost         = types.AttributeKeyCost
+	AttributeKeyPricedCost   = types.AttributeKeyPricedCost
+	AttributeKeyPrice        = types.AttributeKeyPrice
+	AttributeKeyLoad         = types.AttributeKeyLoad
+	AttributeKeyAmount       = types.AttributeKeyAmount
+	AttributeKeyKarma        = types.AttributeKeyKarma
+	AttributeKeyPriority     = types.AttributeKeyPriority
+	AttributeKeyPendingTxs   = types.AttributeKeyPendingTxs
+	AttributeKeyReserved     = types.AttributeKeyReserved
+	AttributeValueCategory   = types.AttributeValueCategory
+	MaxPriceHistoryRecords  = types.MaxPriceHistoryRecords
+	PricingCurveLinear      = types.PricingCurveLinear
+	PricingCurveExponential = types.PricingCurveExponential
+	PricingCurvePiecewise   = types.PricingCurvePiecewise
 )
 
 var (
@@ -33,6 +73,30 @@ var (
 	NewParams			= types.NewParams
 	DefaultParams       = types.DefaultParams
 	NewGenesisAccountBandwidth = types.NewGenesisAccountBandwidth
+	NewQueryPriceHistoryParams = types.NewQueryPriceHistoryParams
+	DefaultPricingCurve        = types.DefaultPricingCurve
+	NewPricingFunction         = types.NewPricingFunction
+	NewMsgCost                 = types.NewMsgCost
+	NewOverrideWhitelistedAccount   = types.NewOverrideWhitelistedAccount
+	NewMultiplierWhitelistedAccount = types.NewMultiplierWhitelistedAccount
+	NewBandwidthEvent          = types.NewBandwidthEvent
+	NewKarmaEvent              = types.NewKarmaEvent
+	NewMempoolBandwidthEvent   = types.NewMempoolBandwidthEvent
+	NewBandwidthPriceEvent     = types.NewBandwidthPriceEvent
+	DefaultMsgCosts            = types.DefaultMsgCosts
+	AddCosts                   = types.AddCosts
+	MulCost                    = types.MulCost
+	RegisterCodec              = types.RegisterCodec
+	NewBandwidthGrant          = types.NewBandwidthGrant
+	ActiveGrantsFraction       = types.ActiveGrantsFraction
+	NewMsgGrantBandwidth       = types.NewMsgGrantBandwidth
+	NewMsgRevokeBandwidth      = types.NewMsgRevokeBandwidth
+	OutgoingGrantsKeyPrefix    = types.OutgoingGrantsKeyPrefix
+	IncomingGrantsKeyPrefix    = types.IncomingGrantsKeyPrefix
+	OutgoingGrantKey           = types.OutgoingGrantKey
+	IncomingGrantKey           = types.IncomingGrantKey
+	PruneHeightKey             = types.PruneHeightKey
+	GenesisUpgrades            = types.GenesisUpgrades
 
 	// variable aliases
 	ModuleCdc             = types.ModuleCdc
@@ -44,9 +108,18 @@ var (
 	KeyBaseCreditPrice    = types.KeyBaseCreditPrice
 	KeyDesirableBandwidth = types.KeyDesirableBandwidth
 	KeyMaxBlockBandwidth  = types.KeyMaxBlockBandwidth
+	KeyPriceHistoryRetention = types.KeyPriceHistoryRetention
+	KeyPricingCurve          = types.KeyPricingCurve
+	KeyMsgCosts              = types.KeyMsgCosts
+	KeyWhitelist             = types.KeyWhitelist
 
 	ErrNotEnoughBandwidth = types.ErrNotEnoughBandwidth
 	ErrExceededMaxBlockBandwidth = types.ErrExceededMaxBlockBandwidth
+	ErrInvalidBandwidthPayer = types.ErrInvalidBandwidthPayer
+	ErrInvalidGrant            = types.ErrInvalidGrant
+	ErrGrantNotFound           = types.ErrGrantNotFound
+	ErrExceededGrantedFraction = types.ErrExceededGrantedFraction
+	ErrTooManyGrants           = types.ErrTooManyGrants
 )
 
 type (
@@ -54,7 +127,21 @@ type (
 	BlockSpentBandwidthKeeper = keeper.BaseBlockSpentBandwidthKeeper
 
 	Meter            = types.BandwidthMeter
-	AccountBandwidth = types.AcсountBandwidth
+	AccountBandwidth = types.AccountBandwidth
+	ResultAccountBandwidth = types.ResultAccountBandwidth
+	PriceRecord            = types.PriceRecord
+	ResultPriceRecord      = types.ResultPriceRecord
+	QueryPriceHistoryParams = types.QueryPriceHistoryParams
+	PricingCurve            = types.PricingCurve
+	PricingFunction         = types.PricingFunction
+	MsgCost                 = types.MsgCost
+	MsgCosts                = types.MsgCosts
+	WhitelistedAccount      = types.WhitelistedAccount
+	BandwidthWhitelist      = types.BandwidthWhitelist
+	BandwidthGrant          = types.BandwidthGrant
+	ResultGrants            = types.ResultGrants
+	MsgGrantBandwidth       = types.MsgGrantBandwidth
+	MsgRevokeBandwidth      = types.MsgRevokeBandwidth
 	GenesisState     = types.GenesisState
 	Params           = types.Params
 )
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/cybercongress/go-cyber/x/bandwidth/internal/types"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -33,6 +34,12 @@ func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 			GetCmdQueryTxCost(cdc),
 			GetCmdQueryLinkMsgCost(cdc),
 			GetCmdQueryNonLinkMsgCost(cdc),
+			GetCmdQueryMsgCosts(cdc),
+			GetCmdQueryAccountBandwidth(cdc),
+			GetCmdQueryGrants(cdc),
+			GetCmdQueryPrice(cdc),
+			GetCmdQueryLoad(cdc),
+			GetCmdQueryPriceHistory(cdc),
 		)...,
 	)
 
@@ -272,4 +279,191 @@ func GetCmdQueryNonLinkMsgCost(cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(cost)
 		},
 	}
-}
\ No newline at end of file
+}
+
+// GetCmdQueryMsgCosts implements a command to return the current bandwidth
+// costs of Msgs by route and type.
+func GetCmdQueryMsgCosts(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "msg-costs",
+		Short: "Query the current bandwidth costs of Msgs by route and type",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryMsgCosts)
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var costs types.MsgCosts
+			if err := cdc.UnmarshalJSON(res, &costs); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(costs)
+		},
+	}
+}
+
+// GetCmdQueryAccountBandwidth implements a command to return the account
+// bandwidth recovered to current block.
+func GetCmdQueryAccountBandwidth(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "account [address]",
+		Short: "Query the current bandwidth of account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			if _, err := sdk.AccAddressFromBech32(args[0]); err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryAccountBandwidth, args[0])
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var bandwidth types.ResultAccountBandwidth
+			if err := cdc.UnmarshalJSON(res, &bandwidth); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(bandwidth)
+		},
+	}
+}
+
+// GetCmdQueryGrants implements a command to return the bandwidth grants
+// of account to other accounts and by them.
+func GetCmdQueryGrants(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "grants [address]",
+		Short: "Query the bandwidth grants of account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			if _, err := sdk.AccAddressFromBech32(args[0]); err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryGrants, args[0])
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var grants types.ResultGrants
+			if err := cdc.UnmarshalJSON(res, &grants); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(grants)
+		},
+	}
+}
+
+// GetCmdQueryPrice implements a command to return the current bandwidth
+// price.
+func GetCmdQueryPrice(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "price",
+		Short: "Query the current bandwidth price",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryPrice)
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var price sdk.Dec
+			if err := cdc.UnmarshalJSON(res, &price); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(price)
+		},
+	}
+}
+
+// GetCmdQueryLoad implements a command to return the current network
+// load.
+func GetCmdQueryLoad(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "load",
+		Short: "Query the current network load",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryLoad)
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			var load sdk.Dec
+			if err := cdc.UnmarshalJSON(res, &load); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(load)
+		},
+	}
+}
+
+const flagLimit = "limit"
+
+// GetCmdQueryPriceHistory implements a command to return the bandwidth price
+// and network load set by price adjustments in heights range.
+func GetCmdQueryPriceHistory(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "price-history [from-height] [to-height]",
+		Short: "Query the bandwidth price and network load history in heights range",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromHeight, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			toHeight, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			limit, err := cmd.Flags().GetInt(flagLimit)
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryPriceHistoryParams(fromHeight, toHeight, limit))
+			if err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryPriceHistory)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			var history []types.ResultPriceRecord
+			if err := cdc.UnmarshalJSON(res, &history); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(history)
+		},
+	}
+
+	cmd.Flags().Int(flagLimit, types.MaxPriceHistoryRecords, "Max number of returned records")
+	return cmd
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bufio"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/cybercongress/go-cyber/x/bandwidth/internal/types"
+)
+
+const flagExpiryHeight = "expiry-height"
+
+// GetTxCmd returns the transaction commands for the bandwidth module.
+func GetTxCmd(cdc *codec.Codec) *cobra.Command {
+	bandwidthTxCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Bandwidth transactions subcommands",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	bandwidthTxCmd.AddCommand(flags.PostCommands(
+		GetCmdGrantBandwidth(cdc),
+		GetCmdRevokeBandwidth(cdc),
+	)...)
+
+	return bandwidthTxCmd
+}
+
+// GetCmdGrantBandwidth implements a command to grant fraction of own max
+// bandwidth to other account.
+func GetCmdGrantBandwidth(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant [grantee] [fraction]",
+		Short: "Grant fraction of own max bandwidth to other account",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+
+			grantee, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			fraction, err := sdk.NewDecFromStr(args[1])
+			if err != nil {
+				return err
+			}
+			expiryHeight, err := cmd.Flags().GetInt64(flagExpiryHeight)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgGrantBandwidth(cliCtx.GetFromAddress(), grantee, fraction, expiryHeight)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().Int64(flagExpiryHeight, 0, "Height grant expires at, grant never expires if zero")
+	return cmd
+}
+
+// GetCmdRevokeBandwidth implements a command to revoke bandwidth grant to
+// other account.
+func GetCmdRevokeBandwidth(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke [grantee]",
+		Short: "Revoke bandwidth grant to other account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+
+			grantee, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgRevokeBandwidth(cliCtx.GetFromAddress(), grantee)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
@@ -2,6 +2,8 @@ package bandwidth
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkbank "github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmwasm/wasmd/x/wasm"
 	"github.com/cybercongress/go-cyber/x/link"
 )
 
@@ -9,8 +11,27 @@ func MsgBandwidthCosts(ctx sdk.Context, params Params, msg sdk.Msg) int64 {
 	switch msg.(type) {
 	case link.Msg:
 		linkMsg := msg.(link.Msg)
-		return int64(len(linkMsg.Links)) * params.LinkMsgCost
+		return MulCost(int64(len(linkMsg.Links)), params.LinkMsgCost)
 	default:
+		if cost, found := params.MsgCosts.Get(msg.Route(), msg.Type()); found {
+			return cost.MsgCost(msgItemsCount(msg))
+		}
 		return params.NonLinkMsgCost
 	}
 }
+
+// items of message ItemCost of msg cost is charged for
+func msgItemsCount(msg sdk.Msg) int64 {
+	switch msg := msg.(type) {
+	case sdkbank.MsgMultiSend:
+		return int64(len(msg.Outputs))
+	case wasm.MsgStoreCode:
+		return int64(len(msg.WASMByteCode))
+	case wasm.MsgInstantiateContract:
+		return int64(len(msg.InitMsg))
+	case wasm.MsgExecuteContract:
+		return int64(len(msg.Msg))
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,82 @@
+package bandwidth
+
+import (
+	"math"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmwasm/wasmd/x/wasm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cybercongress/go-cyber/x/link"
+)
+
+func TestMsgBandwidthCosts(t *testing.T) {
+	params := DefaultParams()
+	addr := sdk.AccAddress([]byte("neuron______________"))
+	coins := sdk.NewCoins(sdk.NewInt64Coin("eul", 1))
+	outputs := func(count int) []bank.Output {
+		result := make([]bank.Output, count)
+		for i := range result {
+			result[i] = bank.NewOutput(addr, coins)
+		}
+		return result
+	}
+
+	cases := []struct {
+		name string
+		msg  sdk.Msg
+		cost int64
+	}{
+		{"links", link.NewMsg(addr, make([]link.Link, 3)), 3 * params.LinkMsgCost},
+		{"not listed msg", bank.NewMsgSend(addr, addr, coins), params.NonLinkMsgCost},
+		{"multisend outputs", bank.NewMsgMultiSend([]bank.Input{bank.NewInput(addr, coins)}, outputs(4)), 500 + 4*100},
+		{"store code", wasm.MsgStoreCode{Sender: addr, WASMByteCode: make([]byte, 1000)}, 5000},
+		{"instantiate payload", wasm.MsgInstantiateContract{Sender: addr, InitMsg: []byte(`{"a":1}`)}, 1000 + 7},
+		{"execute payload", wasm.MsgExecuteContract{Sender: addr, Msg: make([]byte, 300)}, 1000 + 300},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.cost, MsgBandwidthCosts(sdk.Context{}, params, c.msg), c.name)
+	}
+
+	// msgs absent in table cost NonLinkMsgCost
+	params.MsgCosts = MsgCosts{NewMsgCost("wasm", "execute", 10, 2)}
+	multisend := bank.NewMsgMultiSend([]bank.Input{bank.NewInput(addr, coins)}, outputs(4))
+	require.Equal(t, params.NonLinkMsgCost, MsgBandwidthCosts(sdk.Context{}, params, multisend))
+	execute := wasm.MsgExecuteContract{Sender: addr, Msg: make([]byte, 300)}
+	require.Equal(t, int64(10+2*300), MsgBandwidthCosts(sdk.Context{}, params, execute))
+}
+
+func TestCostsAreCapped(t *testing.T) {
+	cost := NewMsgCost("bank", "multisend", MaxMsgCost, MaxMsgCost)
+	require.Equal(t, int64(math.MaxInt64), cost.MsgCost(math.MaxInt64/2))
+	require.Equal(t, int64(math.MaxInt64), MulCost(math.MaxInt64/2, 3))
+
+	params := DefaultParams()
+	params.LinkMsgCost = math.MaxInt64 / 2
+	addr := sdk.AccAddress([]byte("neuron______________"))
+	msg := link.NewMsg(addr, make([]link.Link, 3))
+	require.Equal(t, int64(math.MaxInt64), MsgBandwidthCosts(sdk.Context{}, params, msg))
+
+	// priced cost of capped tx cost is capped too
+	for _, fixedPointMath := range []bool{false, true} {
+		meter := &BaseBandwidthMeter{
+			msgCost:               func(sdk.Context, Params, sdk.Msg) int64 { return math.MaxInt64 },
+			currentCreditPrice:    2,
+			currentCreditPriceDec: sdk.NewDec(2),
+			fixedPointMath:        fixedPointMath,
+		}
+		require.Equal(t, int64(math.MaxInt64), meter.priceCost(math.MaxInt64), "fixed point %v", fixedPointMath)
+		require.Equal(t, int64(200), meter.priceCost(100), "fixed point %v", fixedPointMath)
+	}
+
+	input := createTestInput(t, 200)
+	meter := NewBaseMeter(
+		input.mainKeeper, auth.AccountKeeper{}, input.accKeeper, input.blockKeeper, nil,
+		func(sdk.Context, Params, sdk.Msg) int64 { return math.MaxInt64 },
+	)
+	tx := auth.NewStdTx([]sdk.Msg{msg, msg}, auth.StdFee{}, nil, "")
+	require.Equal(t, int64(math.MaxInt64), meter.GetTxCost(input.ctx, tx))
+}
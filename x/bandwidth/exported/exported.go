@@ -7,8 +7,8 @@ import (
 )
 
 type BaseAccountBandwidthKeeper interface {
-	SetAccountBandwidth(ctx sdk.Context, bandwidth types.AcсountBandwidth)
-	GetAccountBandwidth(ctx sdk.Context, address sdk.AccAddress) (bw types.AcсountBandwidth)
+	SetAccountBandwidth(ctx sdk.Context, bandwidth types.AccountBandwidth) error
+	GetAccountBandwidth(ctx sdk.Context, address sdk.AccAddress) (types.AccountBandwidth, error)
 
 	SetParams(ctx sdk.Context, params types.Params)
 	GetParams(ctx sdk.Context) (params types.Params)
@@ -1,21 +1,65 @@
 package bandwidth
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cybercongress/go-cyber/x/bandwidth/internal/types"
 )
 
 // Genesis accounts should contains fully restored bandwidth on block 0
-func InitAccountsBandwidthGenesis(ctx sdk.Context, handler types.BandwidthMeter,
-	keeper AccountBandwidthKeeper, addresses []sdk.AccAddress, data GenesisState) {
+func InitAccountsBandwidthGenesis(ctx sdk.Context, handler types.BandwidthMeter, keeper AccountBandwidthKeeper,
+	blockKeeper BlockSpentBandwidthKeeper, addresses []sdk.AccAddress, data GenesisState) {
 
 	keeper.SetParams(ctx, data.Params)
+	// upgrades are enabled before accounts bandwidth is set, as they change its encoding and math
+	for _, upgrade := range data.Upgrades {
+		if err := enableGenesisUpgrade(ctx, handler, keeper, blockKeeper, upgrade); err != nil {
+			panic(err)
+		}
+	}
+	// grants are set before accounts bandwidth, as they change accounts max bandwidth
+	for _, grant := range data.Grants {
+		keeper.SetGrant(ctx, grant)
+	}
 	for _, address := range addresses {
 		accMaxBw := handler.GetAccMaxBandwidth(ctx, address)
-		keeper.SetAccountBandwidth(ctx, types.NewGenesisAccountBandwidth(address, accMaxBw))
+		if err := keeper.SetAccountBandwidth(ctx, types.NewGenesisAccountBandwidth(address, accMaxBw)); err != nil {
+			panic(err)
+		}
 	}
 }
 
-func ExportGenesis(ctx sdk.Context, keeper AccountBandwidthKeeper) GenesisState {
-	return NewGenesisState(keeper.GetParams(ctx))
+func enableGenesisUpgrade(ctx sdk.Context, handler types.BandwidthMeter, keeper AccountBandwidthKeeper,
+	blockKeeper BlockSpentBandwidthKeeper, upgrade string) error {
+
+	switch upgrade {
+	case BlockSpentPruningUpgrade:
+		blockKeeper.EnablePruning(ctx)
+	case AccountBandwidthEncodingUpgrade:
+		return keeper.MigrateAccountsBandwidthEncoding(ctx)
+	case FixedPointMathUpgrade:
+		return handler.EnableFixedPointMath(ctx)
+	case SponsoredBandwidthUpgrade:
+		keeper.EnableSponsoredBandwidth(ctx)
+	default:
+		return fmt.Errorf("unknown bandwidth upgrade: %s", upgrade)
+	}
+	return nil
+}
+
+func ExportGenesis(ctx sdk.Context, keeper AccountBandwidthKeeper, blockKeeper BlockSpentBandwidthKeeper) GenesisState {
+	enabled := map[string]bool{
+		BlockSpentPruningUpgrade:        blockKeeper.IsPruningEnabled(ctx),
+		AccountBandwidthEncodingUpgrade: keeper.IsAccountsBandwidthEncodingMigrated(ctx),
+		FixedPointMathUpgrade:           keeper.IsFixedPointMathEnabled(ctx),
+		SponsoredBandwidthUpgrade:       keeper.IsSponsoredBandwidthEnabled(ctx),
+	}
+	upgrades := make([]string, 0)
+	for _, upgrade := range GenesisUpgrades {
+		if enabled[upgrade] {
+			upgrades = append(upgrades, upgrade)
+		}
+	}
+	return NewGenesisState(keeper.GetParams(ctx), keeper.GetAllGrants(ctx), upgrades)
 }
@@ -0,0 +1,39 @@
+package bandwidth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesisWithoutUpgrades(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+
+	data := NewGenesisState(DefaultParams(), nil, nil)
+	InitAccountsBandwidthGenesis(input.ctx, meter, input.accKeeper, input.blockKeeper, nil, data)
+
+	// chains started before upgrades enable them at upgrade heights only
+	require.False(t, input.accKeeper.IsAccountsBandwidthEncodingMigrated(input.ctx))
+	require.False(t, input.accKeeper.IsFixedPointMathEnabled(input.ctx))
+	require.False(t, input.accKeeper.IsSponsoredBandwidthEnabled(input.ctx))
+}
+
+func TestGenesisUpgrades(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+
+	data := DefaultGenesisState()
+	require.NoError(t, ValidateGenesis(data))
+	InitAccountsBandwidthGenesis(input.ctx, meter, input.accKeeper, input.blockKeeper, nil, data)
+
+	require.True(t, input.accKeeper.IsAccountsBandwidthEncodingMigrated(input.ctx))
+	require.True(t, input.accKeeper.IsFixedPointMathEnabled(input.ctx))
+	require.True(t, input.accKeeper.IsSponsoredBandwidthEnabled(input.ctx))
+	require.Equal(t, GenesisUpgrades, ExportGenesis(input.ctx, input.accKeeper, input.blockKeeper).Upgrades)
+
+	data.Upgrades = []string{FixedPointMathUpgrade, "unknown"}
+	require.Error(t, ValidateGenesis(data))
+	data.Upgrades = []string{FixedPointMathUpgrade, FixedPointMathUpgrade}
+	require.Error(t, ValidateGenesis(data))
+}
@@ -0,0 +1,145 @@
+package bandwidth
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cybercongress/go-cyber/x/bandwidth/internal/types"
+)
+
+// NewHandler returns a handler for bandwidth grants messages.
+func NewHandler(k AccountBandwidthKeeper, meter Meter) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+		switch msg := msg.(type) {
+		case types.MsgGrantBandwidth:
+			return handleMsgGrantBandwidth(ctx, k, meter, msg)
+		case types.MsgRevokeBandwidth:
+			return handleMsgRevokeBandwidth(ctx, k, meter, msg)
+		default:
+			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", ModuleName, msg)
+		}
+	}
+}
+
+func handleMsgGrantBandwidth(
+	ctx sdk.Context, k AccountBandwidthKeeper, meter Meter, msg types.MsgGrantBandwidth,
+) (*sdk.Result, error) {
+
+	if msg.ExpiryHeight != 0 && msg.ExpiryHeight <= ctx.BlockHeight() {
+		return nil, sdkerrors.Wrapf(types.ErrInvalidGrant, "expiry height %d is passed", msg.ExpiryHeight)
+	}
+
+	// other active grants of granter, expired ones are removed
+	fraction, count := msg.Fraction, 1
+	for _, grant := range k.GetOutgoingGrants(ctx, msg.Granter) {
+		if grant.Grantee.Equals(msg.Grantee) {
+			continue
+		}
+		if !grant.IsActive(ctx.BlockHeight()) {
+			k.DeleteGrant(ctx, grant.Granter, grant.Grantee)
+			continue
+		}
+		fraction = fraction.Add(grant.Fraction)
+		count++
+	}
+	if fraction.GT(sdk.OneDec()) {
+		return nil, sdkerrors.Wrapf(types.ErrExceededGrantedFraction, "total granted fraction %s", fraction)
+	}
+	if count > types.MaxGrantsPerGranter {
+		return nil, sdkerrors.Wrapf(types.ErrTooManyGrants, "max %d grants per account", types.MaxGrantsPerGranter)
+	}
+
+	// other active grants to grantee, as all of them are summed up to grantee max bandwidth
+	incomingCount := 1
+	for _, grant := range k.GetIncomingGrants(ctx, msg.Grantee) {
+		if grant.Granter.Equals(msg.Granter) {
+			continue
+		}
+		if !grant.IsActive(ctx.BlockHeight()) {
+			k.DeleteGrant(ctx, grant.Granter, grant.Grantee)
+			continue
+		}
+		incomingCount++
+	}
+	if incomingCount > types.MaxGrantsPerGrantee {
+		return nil, sdkerrors.Wrapf(types.ErrTooManyGrants, "max %d grants to account", types.MaxGrantsPerGrantee)
+	}
+
+	err := updateGrant(ctx, meter, msg.Granter, msg.Grantee, func() {
+		k.SetGrant(ctx, types.NewBandwidthGrant(msg.Granter, msg.Grantee, msg.Fraction, msg.ExpiryHeight))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeGrantBandwidth,
+			sdk.NewAttribute(types.AttributeKeyGranter, msg.Granter.String()),
+			sdk.NewAttribute(types.AttributeKeyGrantee, msg.Grantee.String()),
+			sdk.NewAttribute(types.AttributeKeyFraction, msg.Fraction.String()),
+			sdk.NewAttribute(types.AttributeKeyExpiryHeight, strconv.FormatInt(msg.ExpiryHeight, 10)),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Granter.String()),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgRevokeBandwidth(
+	ctx sdk.Context, k AccountBandwidthKeeper, meter Meter, msg types.MsgRevokeBandwidth,
+) (*sdk.Result, error) {
+
+	if _, found := k.GetGrant(ctx, msg.Granter, msg.Grantee); !found {
+		return nil, types.ErrGrantNotFound
+	}
+
+	err := updateGrant(ctx, meter, msg.Granter, msg.Grantee, func() {
+		k.DeleteGrant(ctx, msg.Granter, msg.Grantee)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeRevokeBandwidth,
+			sdk.NewAttribute(types.AttributeKeyGranter, msg.Granter.String()),
+			sdk.NewAttribute(types.AttributeKeyGrantee, msg.Grantee.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Granter.String()),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Bandwidth of both accounts is recovered with max bandwidth before grant change,
+// then max bandwidth is updated to value with changed grant.
+func updateGrant(ctx sdk.Context, meter Meter, granter, grantee sdk.AccAddress, change func()) error {
+	if err := updateAccsMaxBandwidth(ctx, meter, granter, grantee); err != nil {
+		return err
+	}
+	change()
+	return updateAccsMaxBandwidth(ctx, meter, granter, grantee)
+}
+
+func updateAccsMaxBandwidth(ctx sdk.Context, meter Meter, addresses ...sdk.AccAddress) error {
+	for _, address := range addresses {
+		if err := meter.UpdateAccMaxBandwidth(ctx, address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package bandwidth
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxGrantsPerGrantee(t *testing.T) {
+	input := createTestInput(t, 200)
+	grantee := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 0xff))
+	stake := testStakeProvider{}
+	meter := NewBaseMeter(
+		input.mainKeeper, auth.AccountKeeper{}, input.accKeeper, input.blockKeeper, stake, MsgBandwidthCosts,
+	)
+	meter.Load(input.ctx)
+	handler := NewHandler(input.accKeeper, meter)
+
+	grant := func(granterIndex byte, expiryHeight int64) error {
+		granter := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), granterIndex))
+		stake[granter.String()] = 1
+		msg := NewMsgGrantBandwidth(granter, grantee, sdk.NewDecWithPrec(1, 1), expiryHeight)
+		_, err := handler(input.ctx, msg)
+		return err
+	}
+
+	for i := 1; i < MaxGrantsPerGrantee; i++ {
+		require.NoError(t, grant(byte(i), 0))
+	}
+	require.NoError(t, grant(MaxGrantsPerGrantee, 10))
+	require.Error(t, grant(MaxGrantsPerGrantee+1, 0))
+	require.Len(t, input.accKeeper.GetIncomingGrants(input.ctx, grantee), MaxGrantsPerGrantee)
+
+	// granter could update its grant, and expired grants free place for new ones
+	require.NoError(t, grant(1, 0))
+	input.ctx = input.ctx.WithBlockHeight(10)
+	require.NoError(t, grant(MaxGrantsPerGrantee+1, 0))
+	require.Len(t, input.accKeeper.GetIncomingGrants(input.ctx, grantee), MaxGrantsPerGrantee)
+}
@@ -2,7 +2,6 @@ package keeper
 
 import (
 	"encoding/binary"
-	"encoding/json"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -11,44 +10,124 @@ import (
 	"github.com/cybercongress/go-cyber/x/bandwidth/internal/types"
 )
 
+// Accounts bandwidth is kept in store with bare addresses keys, grants and upgrades state in module store.
 type BaseAccountBandwidthKeeper struct {
-	cdc        *codec.Codec
-	storeKey   sdk.StoreKey
-	paramSpace params.Subspace
+	cdc            *codec.Codec
+	storeKey       sdk.StoreKey
+	moduleStoreKey sdk.StoreKey
+	paramSpace     params.Subspace
 }
 
-func NewAccountBandwidthKeeper(cdc *codec.Codec, key sdk.StoreKey, paramSpace params.Subspace) BaseAccountBandwidthKeeper {
+func NewAccountBandwidthKeeper(
+	cdc *codec.Codec, key sdk.StoreKey, moduleKey sdk.StoreKey, paramSpace params.Subspace,
+) BaseAccountBandwidthKeeper {
 	return BaseAccountBandwidthKeeper{
-		cdc:        cdc,
-		storeKey:   key,
-		paramSpace: paramSpace.WithKeyTable(types.ParamKeyTable()),
+		cdc:            cdc,
+		storeKey:       key,
+		moduleStoreKey: moduleKey,
+		paramSpace:     paramSpace.WithKeyTable(types.ParamKeyTable()),
 	}
 }
 
-func (k BaseAccountBandwidthKeeper) SetAccountBandwidth(ctx sdk.Context, bandwidth types.AcсountBandwidth) {
-	bwBytes, _ := json.Marshal(bandwidth)
+func (k BaseAccountBandwidthKeeper) SetAccountBandwidth(ctx sdk.Context, bandwidth types.AccountBandwidth) error {
+	bwBytes, err := types.MarshalAccountBandwidth(k.cdc, bandwidth, k.getEncoding(ctx))
+	if err != nil {
+		return err
+	}
 	ctx.KVStore(k.storeKey).Set(bandwidth.Address, bwBytes)
+	return nil
 }
 
-func (bk BaseAccountBandwidthKeeper) GetAccountBandwidth(ctx sdk.Context, addr sdk.AccAddress) (bw types.AcсountBandwidth) {
+func (bk BaseAccountBandwidthKeeper) GetAccountBandwidth(ctx sdk.Context, addr sdk.AccAddress) (types.AccountBandwidth, error) {
 	bwBytes := ctx.KVStore(bk.storeKey).Get(addr)
 	if bwBytes == nil {
-		return types.AcсountBandwidth{
+		return types.AccountBandwidth{
 			Address:          addr,
 			RemainedValue:    0,
 			LastUpdatedBlock: ctx.BlockHeight(),
 			MaxValue:         0,
+		}, nil
+	}
+	return types.UnmarshalAccountBandwidth(bk.cdc, addr, bwBytes)
+}
+
+func (bk BaseAccountBandwidthKeeper) getEncoding(ctx sdk.Context) byte {
+	encodingBytes := ctx.KVStore(bk.moduleStoreKey).Get(types.AccountBandwidthEncodingKey)
+	if encodingBytes == nil {
+		return types.AccountBandwidthEncodingLegacy
+	}
+	return encodingBytes[0]
+}
+
+func (bk BaseAccountBandwidthKeeper) IsAccountsBandwidthEncodingMigrated(ctx sdk.Context) bool {
+	return bk.getEncoding(ctx) == types.AccountBandwidthEncodingAmino
+}
+
+// Re-encodes all accounts bandwidth with amino encoding, which is used for values written afterwards.
+// Migration is done by upgrade, so state of blocks before upgrade is not changed.
+func (bk BaseAccountBandwidthKeeper) MigrateAccountsBandwidthEncoding(ctx sdk.Context) error {
+	if bk.IsAccountsBandwidthEncodingMigrated(ctx) {
+		return nil
+	}
+
+	store := ctx.KVStore(bk.storeKey)
+	iterator := store.Iterator(nil, nil)
+	accountsBandwidth := make([]types.AccountBandwidth, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		bw, err := types.UnmarshalAccountBandwidth(bk.cdc, iterator.Key(), iterator.Value())
+		if err != nil {
+			iterator.Close()
+			return err
 		}
+		accountsBandwidth = append(accountsBandwidth, bw)
 	}
-	err := json.Unmarshal(bwBytes, &bw)
-	if err != nil {
-		panic("bandwidth index is broken")
+	iterator.Close()
+
+	ctx.KVStore(bk.moduleStoreKey).Set(types.AccountBandwidthEncodingKey, []byte{types.AccountBandwidthEncodingAmino})
+	for _, bw := range accountsBandwidth {
+		if err := bk.SetAccountBandwidth(ctx, bw); err != nil {
+			return err
+		}
 	}
-	return
+	return nil
+}
+
+// Fixed point math is enabled by upgrade, so state of blocks before upgrade is not changed.
+func (bk BaseAccountBandwidthKeeper) EnableFixedPointMath(ctx sdk.Context) {
+	ctx.KVStore(bk.moduleStoreKey).Set(types.FixedPointMathKey, []byte{1})
+}
+
+func (bk BaseAccountBandwidthKeeper) IsFixedPointMathEnabled(ctx sdk.Context) bool {
+	return ctx.KVStore(bk.moduleStoreKey).Has(types.FixedPointMathKey)
+}
+
+// Payer designated in tx memo is respected after upgrade only, before it first signer pays bandwidth.
+func (bk BaseAccountBandwidthKeeper) EnableSponsoredBandwidth(ctx sdk.Context) {
+	ctx.KVStore(bk.moduleStoreKey).Set(types.SponsoredBandwidthKey, []byte{1})
+}
+
+func (bk BaseAccountBandwidthKeeper) IsSponsoredBandwidthEnabled(ctx sdk.Context) bool {
+	return ctx.KVStore(bk.moduleStoreKey).Has(types.SponsoredBandwidthKey)
 }
 
 func (bk BaseAccountBandwidthKeeper) GetParams(ctx sdk.Context) (params types.Params) {
-	bk.paramSpace.GetParamSet(ctx, &params)
+	bk.paramSpace.Get(ctx, types.KeyTxCost, &params.TxCost)
+	bk.paramSpace.Get(ctx, types.KeyLinkMsgCost, &params.LinkMsgCost)
+	bk.paramSpace.Get(ctx, types.KeyNonLinkMsgCost, &params.NonLinkMsgCost)
+	bk.paramSpace.Get(ctx, types.KeyRecoveryPeriod, &params.RecoveryPeriod)
+	bk.paramSpace.Get(ctx, types.KeyAdjustPricePeriod, &params.AdjustPricePeriod)
+	bk.paramSpace.Get(ctx, types.KeyBaseCreditPrice, &params.BaseCreditPrice)
+	bk.paramSpace.Get(ctx, types.KeyDesirableBandwidth, &params.DesirableBandwidth)
+	bk.paramSpace.Get(ctx, types.KeyMaxBlockBandwidth, &params.MaxBlockBandwidth)
+	// absent in state of chains started before price history, zero disables history
+	bk.paramSpace.GetIfExists(ctx, types.KeyPriceHistoryRetention, &params.PriceHistoryRetention)
+	// absent in state of chains started before pricing curves, default linear curve is the original pricing
+	params.PricingCurve = types.DefaultPricingCurve()
+	bk.paramSpace.GetIfExists(ctx, types.KeyPricingCurve, &params.PricingCurve)
+	// absent in state of chains started before msg costs table, all non link msgs cost NonLinkMsgCost then
+	bk.paramSpace.GetIfExists(ctx, types.KeyMsgCosts, &params.MsgCosts)
+	// absent in state of chains started before whitelist, no accounts are whitelisted then
+	bk.paramSpace.GetIfExists(ctx, types.KeyWhitelist, &params.Whitelist)
 	return params
 }
 
@@ -89,9 +168,112 @@ func (bk BaseBlockSpentBandwidthKeeper) GetValuesForPeriod(ctx sdk.Context, peri
 	result := make(map[uint64]uint64)
 	for blockNumber := windowStart; blockNumber <= ctx.BlockHeight(); blockNumber++ {
 		binary.LittleEndian.PutUint64(key, uint64(blockNumber))
-		value := binary.LittleEndian.Uint64(store.Get(key))
-		result[uint64(blockNumber)] = value
+		valueAsBytes := store.Get(key)
+		// values out of previous recovery period window are pruned, such blocks are counted as blocks without spending
+		if valueAsBytes == nil {
+			continue
+		}
+		result[uint64(blockNumber)] = binary.LittleEndian.Uint64(valueAsBytes)
 	}
 
 	return result
 }
+
+// Pruning is enabled by upgrade, so state of blocks before upgrade is not changed.
+func (bk BaseBlockSpentBandwidthKeeper) EnablePruning(ctx sdk.Context) {
+	if bk.IsPruningEnabled(ctx) {
+		return
+	}
+	bk.setPruneHeight(ctx, 1)
+}
+
+func (bk BaseBlockSpentBandwidthKeeper) IsPruningEnabled(ctx sdk.Context) bool {
+	return ctx.KVStore(bk.storeKey).Has(types.PruneHeightKey)
+}
+
+func (bk BaseBlockSpentBandwidthKeeper) getPruneHeight(ctx sdk.Context) uint64 {
+	heightAsBytes := ctx.KVStore(bk.storeKey).Get(types.PruneHeightKey)
+	return binary.LittleEndian.Uint64(heightAsBytes)
+}
+
+func (bk BaseBlockSpentBandwidthKeeper) setPruneHeight(ctx sdk.Context, height uint64) {
+	heightAsBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heightAsBytes, height)
+	ctx.KVStore(bk.storeKey).Set(types.PruneHeightKey, heightAsBytes)
+}
+
+// Deletes values of blocks before window start, at most maxBlocks per call, so values of all blocks
+// before upgrade or before recovery period was decreased are deleted gradually.
+func (bk BaseBlockSpentBandwidthKeeper) Prune(ctx sdk.Context, windowStart uint64, maxBlocks uint64) {
+	if !bk.IsPruningEnabled(ctx) {
+		return
+	}
+
+	store := ctx.KVStore(bk.storeKey)
+	height := bk.getPruneHeight(ctx)
+	if height >= windowStart {
+		return
+	}
+
+	end := windowStart
+	if end-height > maxBlocks {
+		end = height + maxBlocks
+	}
+	key := make([]byte, 8)
+	for ; height < end; height++ {
+		binary.LittleEndian.PutUint64(key, height)
+		store.Delete(key)
+	}
+	bk.setPruneHeight(ctx, end)
+}
+
+// Stores grant under granter outgoing and grantee incoming keys
+func (bk BaseAccountBandwidthKeeper) SetGrant(ctx sdk.Context, grant types.BandwidthGrant) {
+	store := ctx.KVStore(bk.moduleStoreKey)
+	grantBytes := bk.cdc.MustMarshalBinaryBare(grant)
+	store.Set(types.OutgoingGrantKey(grant.Granter, grant.Grantee), grantBytes)
+	store.Set(types.IncomingGrantKey(grant.Grantee, grant.Granter), grantBytes)
+}
+
+func (bk BaseAccountBandwidthKeeper) GetGrant(
+	ctx sdk.Context, granter, grantee sdk.AccAddress,
+) (grant types.BandwidthGrant, found bool) {
+	grantBytes := ctx.KVStore(bk.moduleStoreKey).Get(types.OutgoingGrantKey(granter, grantee))
+	if grantBytes == nil {
+		return grant, false
+	}
+	bk.cdc.MustUnmarshalBinaryBare(grantBytes, &grant)
+	return grant, true
+}
+
+func (bk BaseAccountBandwidthKeeper) DeleteGrant(ctx sdk.Context, granter, grantee sdk.AccAddress) {
+	store := ctx.KVStore(bk.moduleStoreKey)
+	store.Delete(types.OutgoingGrantKey(granter, grantee))
+	store.Delete(types.IncomingGrantKey(grantee, granter))
+}
+
+func (bk BaseAccountBandwidthKeeper) GetOutgoingGrants(ctx sdk.Context, granter sdk.AccAddress) []types.BandwidthGrant {
+	return bk.getGrants(ctx, types.OutgoingGrantsPrefix(granter))
+}
+
+func (bk BaseAccountBandwidthKeeper) GetIncomingGrants(ctx sdk.Context, grantee sdk.AccAddress) []types.BandwidthGrant {
+	return bk.getGrants(ctx, types.IncomingGrantsPrefix(grantee))
+}
+
+// Returns all grants, used for genesis export
+func (bk BaseAccountBandwidthKeeper) GetAllGrants(ctx sdk.Context) []types.BandwidthGrant {
+	return bk.getGrants(ctx, types.OutgoingGrantsKeyPrefix)
+}
+
+func (bk BaseAccountBandwidthKeeper) getGrants(ctx sdk.Context, prefix []byte) []types.BandwidthGrant {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(bk.moduleStoreKey), prefix)
+	defer iterator.Close()
+
+	grants := make([]types.BandwidthGrant, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		var grant types.BandwidthGrant
+		bk.cdc.MustUnmarshalBinaryBare(iterator.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
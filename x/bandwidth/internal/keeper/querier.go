@@ -10,8 +10,8 @@ import (
 )
 
 // NewQuerier returns a minting Querier handler. k exported.StateKeeper
-func NewQuerier(k BaseAccountBandwidthKeeper) sdk.Querier {
-	return func(ctx sdk.Context, path []string, _ abci.RequestQuery) ([]byte, error) {
+func NewQuerier(k BaseAccountBandwidthKeeper, meter types.BandwidthMeter) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, error) {
 		switch path[0] {
 		case types.QueryParameters:
 			return queryParams(ctx, k)
@@ -40,6 +40,23 @@ func NewQuerier(k BaseAccountBandwidthKeeper) sdk.Querier {
 		case types.QueryNonLinkMsgCost:
 			return queryNonLinkMsgCost(ctx, k)
 
+		case types.QueryMsgCosts:
+			return queryMsgCosts(ctx, k)
+
+		case types.QueryAccountBandwidth:
+			return queryAccountBandwidth(ctx, path[1:], k, meter)
+
+		case types.QueryGrants:
+			return queryGrants(ctx, path[1:], k)
+
+		case types.QueryPrice:
+			return queryPrice(meter)
+
+		case types.QueryLoad:
+			return queryLoad(ctx, meter)
+
+		case types.QueryPriceHistory:
+			return queryPriceHistory(ctx, req, meter)
 
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown query path: %s", path[0])
@@ -146,4 +163,131 @@ func queryNonLinkMsgCost(ctx sdk.Context, k BaseAccountBandwidthKeeper) ([]byte,
 	return res, nil
 }
 
+func queryMsgCosts(ctx sdk.Context, k BaseAccountBandwidthKeeper) ([]byte, error) {
+	params := k.GetParams(ctx)
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, params.MsgCosts)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryAccountBandwidth(
+	ctx sdk.Context, path []string, k BaseAccountBandwidthKeeper, meter types.BandwidthMeter,
+) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "address is not provided")
+	}
+
+	address, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+	}
+
+	params := k.GetParams(ctx)
+	accBw, err := meter.GetCurrentAccBandwidth(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	result := types.ResultAccountBandwidth{
+		Address:              address,
+		RemainedValue:        accBw.RemainedValue,
+		MaxValue:             accBw.MaxValue,
+		Linked:               accBw.Linked,
+		BlocksToFullRecovery: accBw.BlocksToFullRecovery(params.RecoveryPeriod, k.IsFixedPointMathEnabled(ctx)),
+	}
+	if whitelisted, ok := params.Whitelist.Get(address); ok {
+		result.Whitelist = &whitelisted
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, result)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryGrants(ctx sdk.Context, path []string, k BaseAccountBandwidthKeeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "address is not provided")
+	}
+
+	address, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+	}
+
+	result := types.ResultGrants{
+		Incoming: k.GetIncomingGrants(ctx, address),
+		Outgoing: k.GetOutgoingGrants(ctx, address),
+	}
 
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, result)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryPrice(meter types.BandwidthMeter) ([]byte, error) {
+	return marshalFloat(meter.GetCurrentCreditPrice())
+}
+
+func queryLoad(ctx sdk.Context, meter types.BandwidthMeter) ([]byte, error) {
+	return marshalFloat(meter.GetCurrentNetworkLoad(ctx))
+}
+
+func queryPriceHistory(ctx sdk.Context, req abci.RequestQuery, meter types.BandwidthMeter) ([]byte, error) {
+	var params types.QueryPriceHistoryParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if params.FromHeight < 0 || params.ToHeight < params.FromHeight {
+		return nil, sdkerrors.Wrapf(
+			sdkerrors.ErrInvalidRequest, "invalid heights range [%d, %d]", params.FromHeight, params.ToHeight,
+		)
+	}
+	if params.Limit <= 0 || params.Limit > types.MaxPriceHistoryRecords {
+		params.Limit = types.MaxPriceHistoryRecords
+	}
+
+	records := meter.GetPriceHistory(ctx, params.FromHeight, params.ToHeight, params.Limit)
+	result := make([]types.ResultPriceRecord, 0, len(records))
+	for _, record := range records {
+		price, err := types.FloatToDec(record.Price)
+		if err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+		}
+		load, err := types.FloatToDec(record.Load)
+		if err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+		}
+		result = append(result, types.ResultPriceRecord{Height: record.Height, Price: price, Load: load})
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, result)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func marshalFloat(value float64) ([]byte, error) {
+	dec, err := types.FloatToDec(value)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, dec)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
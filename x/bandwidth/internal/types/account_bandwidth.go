@@ -1,12 +1,19 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 type MsgBandwidthCost func(ctx sdk.Context, params Params, msg sdk.Msg) int64
 
-type AcсountBandwidth struct {
+type AccountBandwidth struct {
 	Address          sdk.AccAddress `json:"address"`
 	RemainedValue    int64          `json:"remained"`
 	LastUpdatedBlock int64          `json:"last_updated_block"`
@@ -14,8 +21,79 @@ type AcсountBandwidth struct {
 	Linked           int64          `json:"karma"`
 }
 
-func (bs *AcсountBandwidth) UpdateMax(newValue int64, currentBlock int64, recoveryPeriod int64) {
-	bs.Recover(currentBlock, recoveryPeriod)
+// Account bandwidth store encodings. Legacy values are JSON objects with address, values encoded after
+// migration are version byte followed by amino binary of bandwidth values, address is taken from store key.
+const (
+	AccountBandwidthEncodingLegacy byte = 0
+	AccountBandwidthEncodingAmino  byte = 1
+)
+
+// legacy JSON values are objects
+const legacyEncodingFirstByte = '{'
+
+type storedAccountBandwidth struct {
+	RemainedValue    int64
+	LastUpdatedBlock int64
+	MaxValue         int64
+	Linked           int64
+}
+
+func MarshalAccountBandwidth(cdc *codec.Codec, bw AccountBandwidth, encoding byte) ([]byte, error) {
+	switch encoding {
+	case AccountBandwidthEncodingLegacy:
+		return json.Marshal(bw)
+	case AccountBandwidthEncodingAmino:
+		bwBytes, err := cdc.MarshalBinaryBare(storedAccountBandwidth{
+			RemainedValue:    bw.RemainedValue,
+			LastUpdatedBlock: bw.LastUpdatedBlock,
+			MaxValue:         bw.MaxValue,
+			Linked:           bw.Linked,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{AccountBandwidthEncodingAmino}, bwBytes...), nil
+	default:
+		return nil, fmt.Errorf("unknown account bandwidth encoding %d", encoding)
+	}
+}
+
+// Decodes value of address in any of encodings, so store could be read during migration
+func UnmarshalAccountBandwidth(cdc *codec.Codec, address sdk.AccAddress, bwBytes []byte) (bw AccountBandwidth, err error) {
+	if len(bwBytes) == 0 {
+		return bw, sdkerrors.Wrapf(ErrInvalidAccountBandwidth, "empty value of %s", address)
+	}
+
+	switch bwBytes[0] {
+	case legacyEncodingFirstByte:
+		if err = json.Unmarshal(bwBytes, &bw); err != nil {
+			return bw, sdkerrors.Wrapf(ErrInvalidAccountBandwidth, "%s: %v", address, err)
+		}
+		return bw, nil
+	case AccountBandwidthEncodingAmino:
+		var stored storedAccountBandwidth
+		if err = cdc.UnmarshalBinaryBare(bwBytes[1:], &stored); err != nil {
+			return bw, sdkerrors.Wrapf(ErrInvalidAccountBandwidth, "%s: %v", address, err)
+		}
+		return AccountBandwidth{
+			Address:          address,
+			RemainedValue:    stored.RemainedValue,
+			LastUpdatedBlock: stored.LastUpdatedBlock,
+			MaxValue:         stored.MaxValue,
+			Linked:           stored.Linked,
+		}, nil
+	default:
+		return bw, sdkerrors.Wrapf(ErrInvalidAccountBandwidth, "unknown encoding %d of %s", bwBytes[0], address)
+	}
+}
+
+// Fixed point recovery is used after fixed point math upgrade, float recovery keeps state of blocks before it.
+func (bs *AccountBandwidth) UpdateMax(newValue int64, currentBlock int64, recoveryPeriod int64, fixedPoint bool) {
+	if fixedPoint {
+		bs.RecoverFixedPoint(currentBlock, recoveryPeriod)
+	} else {
+		bs.Recover(currentBlock, recoveryPeriod)
+	}
 	bs.MaxValue = newValue
 	bs.LastUpdatedBlock = currentBlock
 
@@ -24,7 +102,7 @@ func (bs *AcсountBandwidth) UpdateMax(newValue int64, currentBlock int64, recov
 	}
 }
 
-func (bs *AcсountBandwidth) Recover(currentBlock int64, recoveryPeriod int64) {
+func (bs *AccountBandwidth) Recover(currentBlock int64, recoveryPeriod int64) {
 	recoverPerBlock := float64(bs.MaxValue) / float64(recoveryPeriod)
 	fullRecoveryAmount := float64(bs.MaxValue - bs.RemainedValue)
 
@@ -37,23 +115,80 @@ func (bs *AcсountBandwidth) Recover(currentBlock int64, recoveryPeriod int64) {
 	bs.LastUpdatedBlock = currentBlock
 }
 
-func (bs AcсountBandwidth) HasEnoughRemained(bandwidthToConsume int64) bool {
+// The same as Recover with integer arithmetic, recovered amount is truncated.
+func (bs *AccountBandwidth) RecoverFixedPoint(currentBlock int64, recoveryPeriod int64) {
+	fullRecoveryAmount := sdk.NewInt(bs.MaxValue - bs.RemainedValue)
+
+	recoverAmount := sdk.NewInt(currentBlock - bs.LastUpdatedBlock).MulRaw(bs.MaxValue).QuoRaw(recoveryPeriod)
+	if recoverAmount.GT(fullRecoveryAmount) {
+		recoverAmount = fullRecoveryAmount
+	}
+
+	bs.RemainedValue = bs.RemainedValue + recoverAmount.Int64()
+	bs.LastUpdatedBlock = currentBlock
+}
+
+// Returns blocks count account bandwidth recovered to current block needs to be fully recovered.
+func (bs AccountBandwidth) BlocksToFullRecovery(recoveryPeriod int64, fixedPoint bool) int64 {
+	fullRecoveryAmount := bs.MaxValue - bs.RemainedValue
+	if fullRecoveryAmount <= 0 {
+		return 0
+	}
+
+	if fixedPoint {
+		// recovered amount max * blocks / period is not less than full recovery amount
+		maxValue := sdk.NewInt(bs.MaxValue)
+		return sdk.NewInt(fullRecoveryAmount).MulRaw(recoveryPeriod).Add(maxValue).SubRaw(1).Quo(maxValue).Int64()
+	}
+
+	recoverPerBlock := float64(bs.MaxValue) / float64(recoveryPeriod)
+	blocks := int64(math.Ceil(float64(fullRecoveryAmount) / recoverPerBlock))
+	// recovered amount is truncated the same way as in Recover
+	for int64(float64(blocks)*recoverPerBlock) < fullRecoveryAmount {
+		blocks++
+	}
+	return blocks
+}
+
+func (bs AccountBandwidth) HasEnoughRemained(bandwidthToConsume int64) bool {
 	return bs.RemainedValue >= bandwidthToConsume
 }
 
-func (bs *AcсountBandwidth) Consume(bandwidthToConsume int64) {
+// Returns mempool priority of tx consuming given bandwidth. Priority is geometric mean of account max bandwidth,
+// which is derived from stake, and bandwidth remained after tx, so it grows with both of them.
+func (bs AccountBandwidth) TxPriority(bandwidthToConsume int64) int64 {
+	remained := bs.RemainedValue - bandwidthToConsume
+	if remained <= 0 || bs.MaxValue <= 0 {
+		return 0
+	}
+	product := new(big.Int).Mul(big.NewInt(bs.MaxValue), big.NewInt(remained))
+	return product.Sqrt(product).Int64()
+}
+
+func (bs *AccountBandwidth) Consume(bandwidthToConsume int64) {
 	bs.RemainedValue = bs.RemainedValue - bandwidthToConsume
 	if bs.RemainedValue < 0 {
 		panic("Negative bandwidth!")
 	}
 }
 
-func (bs *AcсountBandwidth) AddLinked(bandwidthUsed int64) {
+func (bs *AccountBandwidth) AddLinked(bandwidthUsed int64) {
 	bs.Linked = bs.Linked + bandwidthUsed
 }
 
-func NewGenesisAccountBandwidth(address sdk.AccAddress, bandwidth int64) AcсountBandwidth {
-	return AcсountBandwidth{
+// Account bandwidth recovered to query height
+type ResultAccountBandwidth struct {
+	Address              sdk.AccAddress `json:"address"`
+	RemainedValue        int64          `json:"remained"`
+	MaxValue             int64          `json:"max_value"`
+	Linked               int64          `json:"karma"`
+	BlocksToFullRecovery int64          `json:"blocks_to_full_recovery"`
+	// set for accounts with bandwidth override or multiplier
+	Whitelist *WhitelistedAccount `json:"whitelist,omitempty"`
+}
+
+func NewGenesisAccountBandwidth(address sdk.AccAddress, bandwidth int64) AccountBandwidth {
+	return AccountBandwidth{
 		Address:            address,
 		RemainedValue:      bandwidth,
 		MaxValue:           bandwidth,
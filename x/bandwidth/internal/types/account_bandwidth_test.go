@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPriority(t *testing.T) {
+	bw := AccountBandwidth{RemainedValue: 1000, MaxValue: 4000}
+
+	require.Equal(t, int64(2000), bw.TxPriority(0))
+	require.Equal(t, int64(1897), bw.TxPriority(100))
+	// not enough bandwidth
+	require.Equal(t, int64(0), bw.TxPriority(1000))
+	require.Equal(t, int64(0), bw.TxPriority(2000))
+
+	// account with bigger stake has higher priority with the same remained bandwidth
+	require.True(t, AccountBandwidth{RemainedValue: 1000, MaxValue: 8000}.TxPriority(100) > bw.TxPriority(100))
+
+	// no overflow for large values
+	large := AccountBandwidth{RemainedValue: 1 << 62, MaxValue: 1 << 62}
+	require.Equal(t, int64(1<<62), large.TxPriority(0))
+}
+
+func TestRecoverFixedPoint(t *testing.T) {
+	bw := AccountBandwidth{RemainedValue: 0, LastUpdatedBlock: 0, MaxValue: 1000}
+	bw.RecoverFixedPoint(7, 300)
+	// 7 * 1000 / 300 truncated
+	require.Equal(t, int64(23), bw.RemainedValue)
+	require.Equal(t, int64(7), bw.LastUpdatedBlock)
+
+	bw.RecoverFixedPoint(1000, 300)
+	require.Equal(t, int64(1000), bw.RemainedValue)
+
+	// remained over decreased max is cut
+	bw.MaxValue = 500
+	bw.UpdateMax(400, 1001, 300, true)
+	require.Equal(t, int64(400), bw.RemainedValue)
+	require.Equal(t, int64(400), bw.MaxValue)
+}
+
+func TestBlocksToFullRecoveryFixedPoint(t *testing.T) {
+	for _, remained := range []int64{0, 1, 333, 999} {
+		bw := AccountBandwidth{RemainedValue: remained, MaxValue: 1000}
+		blocks := bw.BlocksToFullRecovery(300, true)
+
+		recovered := bw
+		recovered.RecoverFixedPoint(blocks, 300)
+		require.Equal(t, bw.MaxValue, recovered.RemainedValue)
+		notRecovered := bw
+		notRecovered.RecoverFixedPoint(blocks-1, 300)
+		require.True(t, notRecovered.RemainedValue < bw.MaxValue)
+	}
+	require.Equal(t, int64(0), AccountBandwidth{RemainedValue: 1000, MaxValue: 1000}.BlocksToFullRecovery(300, true))
+}
@@ -4,11 +4,18 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 )
 
+// Register concrete types on codec
+func RegisterCodec(cdc *codec.Codec) {
+	cdc.RegisterConcrete(MsgGrantBandwidth{}, "cyber/MsgGrantBandwidth", nil)
+	cdc.RegisterConcrete(MsgRevokeBandwidth{}, "cyber/MsgRevokeBandwidth", nil)
+}
+
 // generic sealed codec to be used throughout this module
 var ModuleCdc *codec.Codec
 
 func init() {
 	ModuleCdc = codec.New()
+	RegisterCodec(ModuleCdc)
 	codec.RegisterCrypto(ModuleCdc)
 	ModuleCdc.Seal()
 }
@@ -8,4 +8,10 @@ var (
 	// NOTE ABCI code 1 is unique. https://github.com/cosmos/cosmos-sdk/issues/5662
 	ErrNotEnoughBandwidth = sdkerrors.Register(ModuleName, 2, "not enough personal bandwidth")
 	ErrExceededMaxBlockBandwidth = sdkerrors.Register(ModuleName, 3, "exceeded max block bandwidth")
+	ErrInvalidBandwidthPayer = sdkerrors.Register(ModuleName, 4, "invalid bandwidth payer")
+	ErrInvalidGrant = sdkerrors.Register(ModuleName, 5, "invalid bandwidth grant")
+	ErrGrantNotFound = sdkerrors.Register(ModuleName, 6, "bandwidth grant not found")
+	ErrExceededGrantedFraction = sdkerrors.Register(ModuleName, 7, "granted bandwidth fraction exceeds one")
+	ErrTooManyGrants = sdkerrors.Register(ModuleName, 8, "too many bandwidth grants")
+	ErrInvalidAccountBandwidth = sdkerrors.Register(ModuleName, 9, "invalid stored account bandwidth")
 )
\ No newline at end of file
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// bandwidth module event types
+const (
+	EventTypeGrantBandwidth   = "grant_bandwidth"
+	EventTypeRevokeBandwidth  = "revoke_bandwidth"
+	EventTypeBandwidth        = "bandwidth"
+	EventTypeKarma            = "karma"
+	EventTypeBandwidthPrice   = "bandwidth_price"
+	EventTypeMempoolBandwidth = "mempool_bandwidth"
+
+	AttributeKeyGranter      = "granter"
+	AttributeKeyGrantee      = "grantee"
+	AttributeKeyFraction     = "fraction"
+	AttributeKeyExpiryHeight = "expiry_height"
+	AttributeKeyAccount      = "account"
+	AttributeKeyCost         = "cost"
+	AttributeKeyPricedCost   = "priced_cost"
+	AttributeKeyPrice        = "price"
+	AttributeKeyLoad         = "load"
+	AttributeKeyAmount       = "amount"
+	AttributeKeyKarma        = "karma"
+	AttributeKeyPriority     = "priority"
+	AttributeKeyPendingTxs   = "pending_txs"
+	AttributeKeyReserved     = "reserved"
+
+	AttributeValueCategory = ModuleName
+)
+
+// Bandwidth spent by account for delivered tx, cost is priced with price of tx block
+func NewBandwidthEvent(account sdk.AccAddress, cost int64, pricedCost int64, price float64) sdk.Event {
+	return sdk.NewEvent(
+		EventTypeBandwidth,
+		sdk.NewAttribute(AttributeKeyAccount, account.String()),
+		sdk.NewAttribute(AttributeKeyCost, strconv.FormatInt(cost, 10)),
+		sdk.NewAttribute(AttributeKeyPricedCost, strconv.FormatInt(pricedCost, 10)),
+		sdk.NewAttribute(AttributeKeyPrice, formatFloat(price)),
+	)
+}
+
+// Priority of tx accepted to mempool, pending txs of account and bandwidth reserved by them including tx
+func NewMempoolBandwidthEvent(account sdk.AccAddress, pricedCost int64, priority int64, pendingTxs int, reserved int64) sdk.Event {
+	return sdk.NewEvent(
+		EventTypeMempoolBandwidth,
+		sdk.NewAttribute(AttributeKeyAccount, account.String()),
+		sdk.NewAttribute(AttributeKeyPricedCost, strconv.FormatInt(pricedCost, 10)),
+		sdk.NewAttribute(AttributeKeyPriority, strconv.FormatInt(priority, 10)),
+		sdk.NewAttribute(AttributeKeyPendingTxs, strconv.Itoa(pendingTxs)),
+		sdk.NewAttribute(AttributeKeyReserved, strconv.FormatInt(reserved, 10)),
+	)
+}
+
+// Karma amount accrued to account and its total karma
+func NewKarmaEvent(account sdk.AccAddress, amount int64, karma int64) sdk.Event {
+	return sdk.NewEvent(
+		EventTypeKarma,
+		sdk.NewAttribute(AttributeKeyAccount, account.String()),
+		sdk.NewAttribute(AttributeKeyAmount, strconv.FormatInt(amount, 10)),
+		sdk.NewAttribute(AttributeKeyKarma, strconv.FormatInt(karma, 10)),
+	)
+}
+
+// Price set by adjustment and network load it is calculated for
+func NewBandwidthPriceEvent(price float64, load float64) sdk.Event {
+	return sdk.NewEvent(
+		EventTypeBandwidthPrice,
+		sdk.NewAttribute(AttributeKeyPrice, formatFloat(price)),
+		sdk.NewAttribute(AttributeKeyLoad, formatFloat(load)),
+	)
+}
+
+// formatted the same way as decimals of queries results
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', sdk.Precision, 64)
+}
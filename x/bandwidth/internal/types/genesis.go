@@ -1,22 +1,86 @@
 package types
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 type GenesisState struct {
-	Params Params `json:"params" yaml:"Params"`
+	Params Params           `json:"params" yaml:"Params"`
+	Grants []BandwidthGrant `json:"grants" yaml:"grants"`
+	// Upgrades enabled from block 0, so new chains or chains started from export don't need them.
+	// Absent in genesis of chains started before upgrades, which enable them at upgrade heights.
+	Upgrades []string `json:"upgrades,omitempty" yaml:"upgrades"`
 }
 
-func NewGenesisState(params Params) GenesisState {
+func NewGenesisState(params Params, grants []BandwidthGrant, upgrades []string) GenesisState {
 	return GenesisState{
-		Params: params,
+		Params:   params,
+		Grants:   grants,
+		Upgrades: upgrades,
 	}
 }
 
 func DefaultGenesisState() GenesisState {
-	return NewGenesisState(DefaultParams())
+	return NewGenesisState(DefaultParams(), nil, GenesisUpgrades)
 }
 
 func ValidateGenesis(data GenesisState) error {
 	if err := data.Params.Validate(); err != nil {
 		return err
 	}
+
+	upgrades := make(map[string]bool)
+	for _, upgrade := range data.Upgrades {
+		if !isGenesisUpgrade(upgrade) {
+			return fmt.Errorf("unknown bandwidth upgrade: %s", upgrade)
+		}
+		if upgrades[upgrade] {
+			return fmt.Errorf("duplicate bandwidth upgrade: %s", upgrade)
+		}
+		upgrades[upgrade] = true
+	}
+
+	granted := make(map[string]sdk.Dec)
+	counts := make(map[string]int)
+	incomingCounts := make(map[string]int)
+	grantees := make(map[string]bool)
+	for _, grant := range data.Grants {
+		if err := grant.Validate(); err != nil {
+			return err
+		}
+		granter := grant.Granter.String()
+		pair := granter + "/" + grant.Grantee.String()
+		if grantees[pair] {
+			return fmt.Errorf("duplicate bandwidth grant: %s", pair)
+		}
+		grantees[pair] = true
+
+		if _, ok := granted[granter]; !ok {
+			granted[granter] = sdk.ZeroDec()
+		}
+		granted[granter] = granted[granter].Add(grant.Fraction)
+		counts[granter]++
+		if granted[granter].GT(sdk.OneDec()) {
+			return fmt.Errorf("granted bandwidth fraction of %s exceeds one", granter)
+		}
+		if counts[granter] > MaxGrantsPerGranter {
+			return fmt.Errorf("too many bandwidth grants of %s", granter)
+		}
+		incomingCounts[grant.Grantee.String()]++
+		if incomingCounts[grant.Grantee.String()] > MaxGrantsPerGrantee {
+			return fmt.Errorf("too many bandwidth grants to %s", grant.Grantee)
+		}
+	}
 	return nil
 }
+
+func isGenesisUpgrade(name string) bool {
+	for _, upgrade := range GenesisUpgrades {
+		if upgrade == name {
+			return true
+		}
+	}
+	return false
+}
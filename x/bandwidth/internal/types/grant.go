@@ -0,0 +1,71 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Max count of outgoing grants of one account
+const MaxGrantsPerGranter = 16
+
+// Max count of incoming grants of one account
+const MaxGrantsPerGrantee = 16
+
+// Fraction of granter own max bandwidth delegated to grantee. Grant with zero expiry height never expires,
+// otherwise it is active up to expiry height exclusive.
+type BandwidthGrant struct {
+	Granter      sdk.AccAddress `json:"granter" yaml:"granter"`
+	Grantee      sdk.AccAddress `json:"grantee" yaml:"grantee"`
+	Fraction     sdk.Dec        `json:"fraction" yaml:"fraction"`
+	ExpiryHeight int64          `json:"expiry_height" yaml:"expiry_height"`
+}
+
+func NewBandwidthGrant(granter, grantee sdk.AccAddress, fraction sdk.Dec, expiryHeight int64) BandwidthGrant {
+	return BandwidthGrant{
+		Granter:      granter,
+		Grantee:      grantee,
+		Fraction:     fraction,
+		ExpiryHeight: expiryHeight,
+	}
+}
+
+func (g BandwidthGrant) IsActive(height int64) bool {
+	return g.ExpiryHeight == 0 || height < g.ExpiryHeight
+}
+
+func (g BandwidthGrant) Validate() error {
+	if err := sdk.VerifyAddressFormat(g.Granter); err != nil {
+		return fmt.Errorf("invalid granter: %s", err)
+	}
+	if err := sdk.VerifyAddressFormat(g.Grantee); err != nil {
+		return fmt.Errorf("invalid grantee: %s", err)
+	}
+	if g.Granter.Equals(g.Grantee) {
+		return fmt.Errorf("bandwidth can't be granted to granter itself: %s", g.Granter)
+	}
+	if g.Fraction.IsNil() || !g.Fraction.IsPositive() || g.Fraction.GT(sdk.OneDec()) {
+		return fmt.Errorf("grant fraction must be in (0, 1]: %s", g.Fraction)
+	}
+	if g.ExpiryHeight < 0 {
+		return fmt.Errorf("grant expiry height must be non-negative: %d", g.ExpiryHeight)
+	}
+	return nil
+}
+
+// Sum of fractions of grants active at given height.
+func ActiveGrantsFraction(grants []BandwidthGrant, height int64) sdk.Dec {
+	fraction := sdk.ZeroDec()
+	for _, grant := range grants {
+		if grant.IsActive(height) {
+			fraction = fraction.Add(grant.Fraction)
+		}
+	}
+	return fraction
+}
+
+// Grants of account by others and to others
+type ResultGrants struct {
+	Incoming []BandwidthGrant `json:"incoming"`
+	Outgoing []BandwidthGrant `json:"outgoing"`
+}
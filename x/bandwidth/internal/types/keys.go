@@ -1,5 +1,9 @@
 package types
 
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 const (
 	// ModuleName is the name of the module
 	ModuleName = "bandwidth"
@@ -10,6 +14,9 @@ const (
 	// StoreKey is the store key string for bandwidth
 	StoreKey = ModuleName
 
+	// RouterKey is the message route for bandwidth
+	RouterKey = ModuleName
+
 	// QuerierRoute is the querier route for the bandwidth store.
 	QuerierRoute = ModuleName
 
@@ -23,4 +30,64 @@ const (
 	QueryTxCost             = "tx_cost"
 	QueryLinkMsgCost        = "link_msg_cost"
 	QueryNonLinkMsgCost     = "non_link_msg_cost"
+	QueryAccountBandwidth   = "account"
+	QueryPrice              = "price"
+	QueryLoad               = "load"
+	QueryPriceHistory       = "price_history"
+	QueryMsgCosts           = "msg_costs"
+	QueryGrants             = "grants"
+
+	// upgrade enabling pruning of spent bandwidth values of blocks out of recovery window
+	BlockSpentPruningUpgrade = "bandwidth-block-spent-pruning"
+
+	// upgrade migrating accounts bandwidth from JSON to versioned amino encoding
+	AccountBandwidthEncodingUpgrade = "bandwidth-account-amino-encoding"
+
+	// upgrade switching bandwidth meter, accounts recovery and price storage to fixed point arithmetic
+	FixedPointMathUpgrade = "bandwidth-fixed-point-math"
+
+	// upgrade enabling bandwidth payer designated in tx memo and karma accrual to linking neurons of tx
+	SponsoredBandwidthUpgrade = "bandwidth-sponsored-txs"
+)
+
+// Upgrades could be enabled by genesis, in order of enabling
+var GenesisUpgrades = []string{
+	BlockSpentPruningUpgrade,
+	AccountBandwidthEncodingUpgrade,
+	FixedPointMathUpgrade,
+	SponsoredBandwidthUpgrade,
+}
+
+// Grants and upgrades state are kept in module store, apart from accounts bandwidth store with bare addresses keys.
+var (
+	OutgoingGrantsKeyPrefix = []byte{0x01} // 0x01 | granter | grantee -> grant
+	IncomingGrantsKeyPrefix = []byte{0x02} // 0x02 | grantee | granter -> grant
 )
+
+// Encoding of accounts bandwidth values, absent until migration from legacy JSON encoding.
+var AccountBandwidthEncodingKey = []byte("encoding_version")
+
+// Marks fixed point bandwidth math is enabled.
+var FixedPointMathKey = []byte("fixed_point_math")
+
+// Marks bandwidth payer designated in tx memo is respected.
+var SponsoredBandwidthKey = []byte("sponsored_bandwidth")
+
+func OutgoingGrantsPrefix(granter sdk.AccAddress) []byte {
+	return append(append([]byte{}, OutgoingGrantsKeyPrefix...), granter...)
+}
+
+func OutgoingGrantKey(granter, grantee sdk.AccAddress) []byte {
+	return append(OutgoingGrantsPrefix(granter), grantee...)
+}
+
+func IncomingGrantsPrefix(grantee sdk.AccAddress) []byte {
+	return append(append([]byte{}, IncomingGrantsKeyPrefix...), grantee...)
+}
+
+func IncomingGrantKey(grantee, granter sdk.AccAddress) []byte {
+	return append(IncomingGrantsPrefix(grantee), granter...)
+}
+
+// Next block to delete spent bandwidth value of, kept in block spent bandwidth store with 8 bytes heights keys
+var PruneHeightKey = []byte("prune_height")
@@ -6,6 +6,8 @@ import (
 
 type AccStakeProvider interface {
 	GetAccStakePercentage(ctx sdk.Context, address sdk.AccAddress) float64
+	GetAccountTotalStake(ctx sdk.Context, address sdk.AccAddress) int64
+	GetTotalSupply(ctx sdk.Context) int64
 }
 
 // General bw handler tx flow
@@ -17,6 +19,8 @@ type AccStakeProvider interface {
 type BandwidthMeter interface {
 	// load current bandwidth state after restart
 	Load(ctx sdk.Context)
+	// switch to fixed point arithmetic and decimal price storage
+	EnableFixedPointMath(ctx sdk.Context) error
 	// add value to consumed bandwidth for current block
 	AddToBlockBandwidth(value int64)
 	// add value to overall linked bandwidth
@@ -27,14 +31,16 @@ type BandwidthMeter interface {
 	GetCurrentCreditPrice() float64
 	// get used in window band proportional to desirable
 	GetCurrentNetworkLoad(ctx sdk.Context) float64
+	// get price adjustments with heights in [fromHeight, toHeight], at most limit records
+	GetPriceHistory(ctx sdk.Context, fromHeight int64, toHeight int64, limit int) []PriceRecord
 	// commit bandwidth value spent for current block
 	CommitBlockBandwidth(ctx sdk.Context)
 	// commit bandwidth value spent for current block
 	CommitTotalKarma(ctx sdk.Context)
 	// Update acc max bandwidth for current stake. Also, performs recover.
-	UpdateAccMaxBandwidth(ctx sdk.Context, address sdk.AccAddress)
+	UpdateAccMaxBandwidth(ctx sdk.Context, address sdk.AccAddress) error
 	// Returns recovered to current block height acc bandwidth
-	GetCurrentAccBandwidth(ctx sdk.Context, address sdk.AccAddress) AcсountBandwidth
+	GetCurrentAccBandwidth(ctx sdk.Context, address sdk.AccAddress) (AccountBandwidth, error)
 	// Returns acc max bandwidth
 	GetAccMaxBandwidth(ctx sdk.Context, address sdk.AccAddress) int64
 	// Returns tx bandwidth cost
@@ -55,8 +61,8 @@ type BandwidthMeter interface {
 	// bw := getCurrentBw(addr)
 	// bwCost := deliverTx(tx)
 	// consumeBw(bw, bwCost)
-	ConsumeAccBandwidth(ctx sdk.Context, bw AcсountBandwidth, amt int64)
+	ConsumeAccBandwidth(ctx sdk.Context, bw AccountBandwidth, amt int64) error
 	// Performs updating of total bandwidth used by account for linking
-	UpdateLinkedBandwidth(ctx sdk.Context, bw AcсountBandwidth, amt int64)
+	UpdateLinkedBandwidth(ctx sdk.Context, bw AccountBandwidth, amt int64) error
 
 }
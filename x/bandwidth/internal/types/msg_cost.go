@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Upper bound of msg cost and item cost, so cost of message with bounded items count doesn't overflow.
+const MaxMsgCost = int64(1) << 32
+
+// Bandwidth cost of messages with given route and type. Cost of message is Cost plus ItemCost
+// for each item of message, such as outputs of multisend or bytes of contract message.
+type MsgCost struct {
+	Route    string `json:"route" yaml:"route"`
+	Type     string `json:"type" yaml:"type"`
+	Cost     int64  `json:"cost" yaml:"cost"`
+	ItemCost int64  `json:"item_cost" yaml:"item_cost"`
+}
+
+func NewMsgCost(route, msgType string, cost, itemCost int64) MsgCost {
+	return MsgCost{
+		Route:    route,
+		Type:     msgType,
+		Cost:     cost,
+		ItemCost: itemCost,
+	}
+}
+
+// Cost of message with given items count, capped by math.MaxInt64 instead of overflowing.
+func (c MsgCost) MsgCost(items int64) int64 {
+	return AddCosts(c.Cost, MulCost(items, c.ItemCost))
+}
+
+func (c MsgCost) String() string {
+	return fmt.Sprintf("%s/%s: %d + %d per item", c.Route, c.Type, c.Cost, c.ItemCost)
+}
+
+// Costs of messages types, messages absent in table cost NonLinkMsgCost.
+type MsgCosts []MsgCost
+
+func DefaultMsgCosts() MsgCosts {
+	return MsgCosts{
+		NewMsgCost("bank", "multisend", 500, 100),
+		NewMsgCost("wasm", "store-code", 5000, 0),
+		NewMsgCost("wasm", "instantiate", 1000, 1),
+		NewMsgCost("wasm", "execute", 1000, 1),
+	}
+}
+
+func (cs MsgCosts) Get(route, msgType string) (MsgCost, bool) {
+	for _, c := range cs {
+		if c.Route == route && c.Type == msgType {
+			return c, true
+		}
+	}
+	return MsgCost{}, false
+}
+
+func (cs MsgCosts) Validate() error {
+	seen := make(map[string]bool)
+	for _, c := range cs {
+		if c.Route == "" || c.Type == "" {
+			return fmt.Errorf("msg cost route and type must be set: %s", c)
+		}
+		if c.Cost < 0 || c.ItemCost < 0 {
+			return fmt.Errorf("msg cost must be non-negative: %s", c)
+		}
+		if c.Cost > MaxMsgCost || c.ItemCost > MaxMsgCost {
+			return fmt.Errorf("msg cost too high: %s, max %d", c, MaxMsgCost)
+		}
+		key := c.Route + "/" + c.Type
+		if seen[key] {
+			return fmt.Errorf("duplicate msg cost: %s", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (cs MsgCosts) String() string {
+	costs := make([]string, 0, len(cs))
+	for _, c := range cs {
+		costs = append(costs, c.String())
+	}
+	return strings.Join(costs, ", ")
+}
+
+// Sum of non-negative costs, capped by math.MaxInt64 instead of overflowing.
+func AddCosts(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// Cost of count items of given non-negative cost, capped by math.MaxInt64 instead of overflowing.
+func MulCost(count, cost int64) int64 {
+	if count != 0 && cost > math.MaxInt64/count {
+		return math.MaxInt64
+	}
+	return count * cost
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgCostsValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		costs MsgCosts
+		valid bool
+	}{
+		{"default", DefaultMsgCosts(), true},
+		{"empty", MsgCosts{}, true},
+		{"max costs", MsgCosts{NewMsgCost("bank", "multisend", MaxMsgCost, MaxMsgCost)}, true},
+		{"no route", MsgCosts{NewMsgCost("", "multisend", 1, 1)}, false},
+		{"no type", MsgCosts{NewMsgCost("bank", "", 1, 1)}, false},
+		{"negative cost", MsgCosts{NewMsgCost("bank", "multisend", -1, 1)}, false},
+		{"negative item cost", MsgCosts{NewMsgCost("bank", "multisend", 1, -1)}, false},
+		{"too high cost", MsgCosts{NewMsgCost("bank", "multisend", MaxMsgCost+1, 1)}, false},
+		{"too high item cost", MsgCosts{NewMsgCost("bank", "multisend", 1, math.MaxInt64)}, false},
+		{"duplicate", MsgCosts{NewMsgCost("wasm", "execute", 1, 1), NewMsgCost("wasm", "execute", 2, 2)}, false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.valid, c.costs.Validate() == nil, c.name)
+	}
+}
+
+func TestMsgCost(t *testing.T) {
+	cost := NewMsgCost("bank", "multisend", 500, 100)
+	require.Equal(t, int64(500), cost.MsgCost(0))
+	require.Equal(t, int64(1500), cost.MsgCost(10))
+
+	// costs are capped instead of overflowing to negative ones
+	require.Equal(t, int64(math.MaxInt64), AddCosts(math.MaxInt64, 1))
+	require.Equal(t, int64(math.MaxInt64), AddCosts(math.MaxInt64-1, 1))
+	require.Equal(t, int64(math.MaxInt64), MulCost(math.MaxInt64/2+1, 2))
+	require.Equal(t, int64(math.MaxInt64-1), MulCost(math.MaxInt64/2, 2))
+	require.Equal(t, int64(0), MulCost(0, math.MaxInt64))
+	require.Equal(t, int64(math.MaxInt64), NewMsgCost("wasm", "execute", MaxMsgCost, MaxMsgCost).MsgCost(math.MaxInt64/MaxMsgCost))
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Grants fraction of granter max bandwidth to grantee, replaces existing grant to the same grantee.
+type MsgGrantBandwidth struct {
+	Granter      sdk.AccAddress `json:"granter" yaml:"granter"`
+	Grantee      sdk.AccAddress `json:"grantee" yaml:"grantee"`
+	Fraction     sdk.Dec        `json:"fraction" yaml:"fraction"`
+	ExpiryHeight int64          `json:"expiry_height" yaml:"expiry_height"`
+}
+
+var _ sdk.Msg = MsgGrantBandwidth{}
+
+func NewMsgGrantBandwidth(granter, grantee sdk.AccAddress, fraction sdk.Dec, expiryHeight int64) MsgGrantBandwidth {
+	return MsgGrantBandwidth{
+		Granter:      granter,
+		Grantee:      grantee,
+		Fraction:     fraction,
+		ExpiryHeight: expiryHeight,
+	}
+}
+
+func (MsgGrantBandwidth) Route() string { return RouterKey }
+func (MsgGrantBandwidth) Type() string  { return "grant_bandwidth" }
+
+func (msg MsgGrantBandwidth) ValidateBasic() error {
+	if msg.Granter.Empty() || msg.Grantee.Empty() {
+		return sdkerrors.ErrInvalidAddress
+	}
+	grant := NewBandwidthGrant(msg.Granter, msg.Grantee, msg.Fraction, msg.ExpiryHeight)
+	if err := grant.Validate(); err != nil {
+		return sdkerrors.Wrap(ErrInvalidGrant, err.Error())
+	}
+	return nil
+}
+
+func (msg MsgGrantBandwidth) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+func (msg MsgGrantBandwidth) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
+
+// Revokes granter grant to grantee.
+type MsgRevokeBandwidth struct {
+	Granter sdk.AccAddress `json:"granter" yaml:"granter"`
+	Grantee sdk.AccAddress `json:"grantee" yaml:"grantee"`
+}
+
+var _ sdk.Msg = MsgRevokeBandwidth{}
+
+func NewMsgRevokeBandwidth(granter, grantee sdk.AccAddress) MsgRevokeBandwidth {
+	return MsgRevokeBandwidth{
+		Granter: granter,
+		Grantee: grantee,
+	}
+}
+
+func (MsgRevokeBandwidth) Route() string { return RouterKey }
+func (MsgRevokeBandwidth) Type() string  { return "revoke_bandwidth" }
+
+func (msg MsgRevokeBandwidth) ValidateBasic() error {
+	if msg.Granter.Empty() || msg.Grantee.Empty() {
+		return sdkerrors.ErrInvalidAddress
+	}
+	return nil
+}
+
+func (msg MsgRevokeBandwidth) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+func (msg MsgRevokeBandwidth) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
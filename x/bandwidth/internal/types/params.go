@@ -17,6 +17,10 @@ var (
 	KeyBaseCreditPrice    = []byte("BaseCreditPrice")
 	KeyDesirableBandwidth = []byte("DesirableBandwidth")
 	KeyMaxBlockBandwidth  = []byte("MaxBlockBandwidth")
+	KeyPriceHistoryRetention = []byte("PriceHistoryRetention")
+	KeyPricingCurve          = []byte("PricingCurve")
+	KeyMsgCosts              = []byte("MsgCosts")
+	KeyWhitelist             = []byte("Whitelist")
 )
 
 // Params defines the parameters for the bandwidth module.
@@ -30,6 +34,14 @@ type Params struct {
 	BaseCreditPrice    sdk.Dec `json:"base_credit_price" yaml:"base_credit_price"`
 	DesirableBandwidth int64   `json:"desirable_bandwidth" yaml:"desirable_bandwidth"`
 	MaxBlockBandwidth  uint64  `json:"max_block_bandwidth" yaml:"max_block_bandwidth"`
+	// blocks count price adjustments are kept in history for, zero disables history
+	PriceHistoryRetention int64 `json:"price_history_retention" yaml:"price_history_retention"`
+	// curve used to calculate price on price adjustment
+	PricingCurve PricingCurve `json:"pricing_curve" yaml:"pricing_curve"`
+	// costs of non link messages by route and type, NonLinkMsgCost is used for other messages
+	MsgCosts MsgCosts `json:"msg_costs" yaml:"msg_costs"`
+	// accounts with bandwidth override or multiplier
+	Whitelist BandwidthWhitelist `json:"whitelist" yaml:"whitelist"`
 }
 
 func ParamKeyTable() params.KeyTable {
@@ -45,6 +57,10 @@ func NewParams(
 	baseCreditPrice    sdk.Dec,
 	desirableBandwidth int64,
 	maxBlockBandwidth  uint64,
+	priceHistoryRetention int64,
+	pricingCurve PricingCurve,
+	msgCosts MsgCosts,
+	whitelist BandwidthWhitelist,
 ) Params {
 
 	return Params{
@@ -56,6 +72,10 @@ func NewParams(
 		BaseCreditPrice:    baseCreditPrice,
 		DesirableBandwidth: desirableBandwidth,
 		MaxBlockBandwidth:  maxBlockBandwidth,
+		PriceHistoryRetention: priceHistoryRetention,
+		PricingCurve:          pricingCurve,
+		MsgCosts:              msgCosts,
+		Whitelist:             whitelist,
 	}
 }
 
@@ -69,6 +89,10 @@ func DefaultParams() Params {
 		BaseCreditPrice:    sdk.NewDec(1),
 		DesirableBandwidth: int64(2000000000),
 		MaxBlockBandwidth:  uint64(125000),
+		PriceHistoryRetention: int64(100000),
+		PricingCurve:          DefaultPricingCurve(),
+		MsgCosts:              DefaultMsgCosts(),
+		Whitelist:             BandwidthWhitelist{},
 	}
 }
 
@@ -98,6 +122,18 @@ func (p Params) Validate() error {
 	if err := validateMaxBlockBanwidth(p.MaxBlockBandwidth); err != nil {
 		return err
 	}
+	if err := validatePriceHistoryRetention(p.PriceHistoryRetention); err != nil {
+		return err
+	}
+	if err := validatePricingCurve(p.PricingCurve); err != nil {
+		return err
+	}
+	if err := validateMsgCosts(p.MsgCosts); err != nil {
+		return err
+	}
+	if err := validateWhitelist(p.Whitelist); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -113,10 +149,15 @@ func (p Params) String() string {
   BaseCreditPrice:    %d
   DesirableBandwidth: %d
   MaxBlockBandidth:   %d
+  PriceHistoryRetention: %d
+  PricingCurve:       %s
+  MsgCosts:           %s
+  Whitelist:          %s
 `,
 		p.LinkMsgCost, p.RecoveryPeriod, p.AdjustPricePeriod,
 		p.BaseCreditPrice, p.DesirableBandwidth, p.MaxBlockBandwidth,
-		p.TxCost, p.NonLinkMsgCost,
+		p.TxCost, p.NonLinkMsgCost, p.PriceHistoryRetention,
+		p.PricingCurve.Type, p.MsgCosts, p.Whitelist,
 	)
 }
 
@@ -233,6 +274,50 @@ func validateMaxBlockBanwidth(i interface{}) error {
 	return nil
 }
 
+func validatePriceHistoryRetention(i interface{}) error {
+	v, ok := i.(int64)
+
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("price history retention must be non-negative: %d", v)
+	}
+
+	return nil
+}
+
+func validatePricingCurve(i interface{}) error {
+	v, ok := i.(PricingCurve)
+
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return v.Validate()
+}
+
+func validateMsgCosts(i interface{}) error {
+	v, ok := i.(MsgCosts)
+
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return v.Validate()
+}
+
+func validateWhitelist(i interface{}) error {
+	v, ok := i.(BandwidthWhitelist)
+
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return v.Validate()
+}
+
 func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
 		params.NewParamSetPair(KeyTxCost, &p.TxCost, validateTxCost),
@@ -243,5 +328,9 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		params.NewParamSetPair(KeyBaseCreditPrice, &p.BaseCreditPrice, validateBaseCreditPrice),
 		params.NewParamSetPair(KeyDesirableBandwidth, &p.DesirableBandwidth, validateDesirableBandwidth),
 		params.NewParamSetPair(KeyMaxBlockBandwidth, &p.MaxBlockBandwidth, validateMaxBlockBanwidth),
+		params.NewParamSetPair(KeyPriceHistoryRetention, &p.PriceHistoryRetention, validatePriceHistoryRetention),
+		params.NewParamSetPair(KeyPricingCurve, &p.PricingCurve, validatePricingCurve),
+		params.NewParamSetPair(KeyMsgCosts, &p.MsgCosts, validateMsgCosts),
+		params.NewParamSetPair(KeyWhitelist, &p.Whitelist, validateWhitelist),
 	}
 }
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// max records returned by one price history query
+const MaxPriceHistoryRecords = 1000
+
+// Price and network load set by price adjustment at block height
+type PriceRecord struct {
+	Height int64
+	Price  float64
+	Load   float64
+}
+
+type QueryPriceHistoryParams struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+	Limit      int   `json:"limit"`
+}
+
+func NewQueryPriceHistoryParams(fromHeight, toHeight int64, limit int) QueryPriceHistoryParams {
+	return QueryPriceHistoryParams{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		Limit:      limit,
+	}
+}
+
+type ResultPriceRecord struct {
+	Height int64   `json:"height"`
+	Price  sdk.Dec `json:"price"`
+	Load   sdk.Dec `json:"load"`
+}
+
+// amino doesn't support floats, so they are returned as decimals
+func FloatToDec(value float64) (sdk.Dec, error) {
+	return sdk.NewDecFromStr(strconv.FormatFloat(value, 'f', sdk.Precision, 64))
+}
+
+func DecToFloat(value sdk.Dec) (float64, error) {
+	return strconv.ParseFloat(value.String(), 64)
+}
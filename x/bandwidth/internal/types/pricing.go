@@ -0,0 +1,176 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Pricing curves names
+const (
+	// price equals network load
+	PricingCurveLinear = "linear"
+	// price makes bounded EIP-1559 style step from previous price toward load of desirable bandwidth
+	PricingCurveExponential = "exponential"
+	// price equals load up to kink, grows with multiplied slope after kink and is capped
+	PricingCurvePiecewise = "piecewise"
+)
+
+// Pricing curve selected by governance with configuration of all curves.
+type PricingCurve struct {
+	Type              string  `json:"type" yaml:"type"`
+	ChangeDenominator int64   `json:"change_denominator" yaml:"change_denominator"`
+	Kink              sdk.Dec `json:"kink" yaml:"kink"`
+	KinkMultiplier    sdk.Dec `json:"kink_multiplier" yaml:"kink_multiplier"`
+	MaxPrice          sdk.Dec `json:"max_price" yaml:"max_price"`
+}
+
+func DefaultPricingCurve() PricingCurve {
+	return PricingCurve{
+		Type:              PricingCurveLinear,
+		ChangeDenominator: int64(8),
+		Kink:              sdk.OneDec(),
+		KinkMultiplier:    sdk.NewDec(4),
+		MaxPrice:          sdk.NewDec(10),
+	}
+}
+
+func (c PricingCurve) Validate() error {
+	if _, err := NewPricingFunction(c); err != nil {
+		return err
+	}
+	if c.ChangeDenominator < 1 {
+		return fmt.Errorf("change denominator must be positive: %d", c.ChangeDenominator)
+	}
+	if c.Kink.IsNil() || !c.Kink.IsPositive() {
+		return fmt.Errorf("kink must be positive: %s", c.Kink)
+	}
+	if c.KinkMultiplier.IsNil() || c.KinkMultiplier.LT(sdk.OneDec()) {
+		return fmt.Errorf("kink multiplier must be at least one: %s", c.KinkMultiplier)
+	}
+	if c.MaxPrice.IsNil() || !c.MaxPrice.IsPositive() {
+		return fmt.Errorf("max price must be positive: %s", c.MaxPrice)
+	}
+	return nil
+}
+
+// Calculates bandwidth price on price adjustment. Before fixed point math upgrade price is stored as float,
+// but all curves except linear one are calculated with decimals, so price doesn't depend on platform
+// float operations. After upgrade price is stored and calculated as decimal by all curves.
+type PricingFunction interface {
+	// returns new price for bandwidth spent in sliding window, price is never lower than min price
+	NextPrice(spent uint64, desirable int64, currentPrice float64, minPrice float64) float64
+	// the same as NextPrice with decimal prices
+	NextPriceDec(spent uint64, desirable int64, currentPrice sdk.Dec, minPrice sdk.Dec) sdk.Dec
+}
+
+func NewPricingFunction(curve PricingCurve) (PricingFunction, error) {
+	switch curve.Type {
+	case PricingCurveLinear:
+		return LinearPricing{}, nil
+	case PricingCurveExponential:
+		return ExponentialPricing{ChangeDenominator: curve.ChangeDenominator}, nil
+	case PricingCurvePiecewise:
+		return PiecewisePricing{Kink: curve.Kink, KinkMultiplier: curve.KinkMultiplier, MaxPrice: curve.MaxPrice}, nil
+	default:
+		return nil, fmt.Errorf("unknown pricing curve: %s", curve.Type)
+	}
+}
+
+// Original pricing, keeps float calculation to produce the same prices on existing chains.
+type LinearPricing struct{}
+
+func (LinearPricing) NextPrice(spent uint64, desirable int64, _ float64, minPrice float64) float64 {
+	price := float64(spent) / float64(desirable)
+	if price < minPrice {
+		price = minPrice
+	}
+	return price
+}
+
+func (LinearPricing) NextPriceDec(spent uint64, desirable int64, _ sdk.Dec, minPrice sdk.Dec) sdk.Dec {
+	price := NetworkLoad(spent, desirable)
+	if price.LT(minPrice) {
+		price = minPrice
+	}
+	return price
+}
+
+// Price is multiplied by 1 + (load - 1) / ChangeDenominator, where load deviation is bounded to [-1, 1],
+// so price changes at most by 1/ChangeDenominator of previous price per adjustment.
+type ExponentialPricing struct {
+	ChangeDenominator int64
+}
+
+func (p ExponentialPricing) NextPrice(spent uint64, desirable int64, currentPrice float64, minPrice float64) float64 {
+	return decToFloat(p.NextPriceDec(spent, desirable, mustFloatToDec(currentPrice), mustFloatToDec(minPrice)))
+}
+
+func (p ExponentialPricing) NextPriceDec(spent uint64, desirable int64, currentPrice sdk.Dec, minPrice sdk.Dec) sdk.Dec {
+	price := currentPrice
+	if price.LT(minPrice) {
+		price = minPrice
+	}
+
+	deviation := NetworkLoad(spent, desirable).Sub(sdk.OneDec())
+	if deviation.GT(sdk.OneDec()) {
+		deviation = sdk.OneDec()
+	}
+	if deviation.LT(sdk.OneDec().Neg()) {
+		deviation = sdk.OneDec().Neg()
+	}
+
+	price = price.Mul(sdk.OneDec().Add(deviation.QuoInt64(p.ChangeDenominator)))
+	if price.LT(minPrice) {
+		price = minPrice
+	}
+	return price
+}
+
+// Price equals load up to Kink, after it grows KinkMultiplier times faster and is capped at MaxPrice.
+// Min price has priority over MaxPrice.
+type PiecewisePricing struct {
+	Kink           sdk.Dec
+	KinkMultiplier sdk.Dec
+	MaxPrice       sdk.Dec
+}
+
+func (p PiecewisePricing) NextPrice(spent uint64, desirable int64, currentPrice float64, minPrice float64) float64 {
+	return decToFloat(p.NextPriceDec(spent, desirable, mustFloatToDec(currentPrice), mustFloatToDec(minPrice)))
+}
+
+func (p PiecewisePricing) NextPriceDec(spent uint64, desirable int64, _ sdk.Dec, minPrice sdk.Dec) sdk.Dec {
+	price := NetworkLoad(spent, desirable)
+	if price.GT(p.Kink) {
+		price = p.Kink.Add(price.Sub(p.Kink).Mul(p.KinkMultiplier))
+	}
+	if price.GT(p.MaxPrice) {
+		price = p.MaxPrice
+	}
+	if price.LT(minPrice) {
+		price = minPrice
+	}
+	return price
+}
+
+// Bandwidth spent in sliding window proportional to desirable bandwidth
+func NetworkLoad(spent uint64, desirable int64) sdk.Dec {
+	return sdk.NewDecFromInt(sdk.NewIntFromUint64(spent)).QuoInt64(desirable)
+}
+
+// prices are finite, so conversion doesn't fail
+func mustFloatToDec(value float64) sdk.Dec {
+	dec, err := FloatToDec(value)
+	if err != nil {
+		panic(err)
+	}
+	return dec
+}
+
+func decToFloat(value sdk.Dec) float64 {
+	f, err := DecToFloat(value)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+const desirable = int64(1000)
+const minPrice = 0.01
+
+func curveOfType(curveType string) PricingCurve {
+	curve := DefaultPricingCurve()
+	curve.Type = curveType
+	return curve
+}
+
+func TestLinearPricing(t *testing.T) {
+	pricing, err := NewPricingFunction(curveOfType(PricingCurveLinear))
+	require.NoError(t, err)
+
+	// floored below min price
+	require.Equal(t, minPrice, pricing.NextPrice(0, desirable, 5, minPrice))
+	require.Equal(t, minPrice, pricing.NextPrice(9, desirable, 5, minPrice))
+	require.Equal(t, minPrice, pricing.NextPrice(10, desirable, 5, minPrice))
+	// equals load above min price, current price is ignored
+	require.Equal(t, 0.011, pricing.NextPrice(11, desirable, 5, minPrice))
+	require.Equal(t, 1.0, pricing.NextPrice(1000, desirable, 5, minPrice))
+	require.Equal(t, 3.0, pricing.NextPrice(3000, desirable, 5, minPrice))
+}
+
+func TestExponentialPricing(t *testing.T) {
+	pricing, err := NewPricingFunction(curveOfType(PricingCurveExponential))
+	require.NoError(t, err)
+
+	// unchanged at desirable load
+	require.Equal(t, 2.0, pricing.NextPrice(1000, desirable, 2, minPrice))
+	// step toward load
+	require.Equal(t, 2.125, pricing.NextPrice(1500, desirable, 2, minPrice))
+	require.Equal(t, 1.875, pricing.NextPrice(500, desirable, 2, minPrice))
+	// step is bounded by 1/8 of price
+	require.Equal(t, 2.25, pricing.NextPrice(2000, desirable, 2, minPrice))
+	require.Equal(t, 2.25, pricing.NextPrice(100000, desirable, 2, minPrice))
+	require.Equal(t, 1.75, pricing.NextPrice(0, desirable, 2, minPrice))
+	// floored at min price
+	require.Equal(t, minPrice, pricing.NextPrice(0, desirable, minPrice, minPrice))
+	require.Equal(t, minPrice, pricing.NextPrice(0, desirable, 0.0105, minPrice))
+	// current price lower than min price is raised to min price before step
+	require.Equal(t, 0.01125, pricing.NextPrice(2000, desirable, 0.001, minPrice))
+}
+
+func TestPiecewisePricing(t *testing.T) {
+	pricing, err := NewPricingFunction(curveOfType(PricingCurvePiecewise))
+	require.NoError(t, err)
+
+	// floored below min price
+	require.Equal(t, minPrice, pricing.NextPrice(0, desirable, 5, minPrice))
+	require.Equal(t, minPrice, pricing.NextPrice(10, desirable, 5, minPrice))
+	// equals load up to kink
+	require.Equal(t, 0.5, pricing.NextPrice(500, desirable, 5, minPrice))
+	require.Equal(t, 1.0, pricing.NextPrice(1000, desirable, 5, minPrice))
+	// slope is multiplied after kink
+	require.Equal(t, 1.004, pricing.NextPrice(1001, desirable, 5, minPrice))
+	require.Equal(t, 5.0, pricing.NextPrice(2000, desirable, 5, minPrice))
+	// capped at max price
+	require.Equal(t, 10.0, pricing.NextPrice(3250, desirable, 5, minPrice))
+	require.Equal(t, 10.0, pricing.NextPrice(3251, desirable, 5, minPrice))
+	require.Equal(t, 10.0, pricing.NextPrice(1000000, desirable, 5, minPrice))
+	// min price has priority over cap
+	require.Equal(t, 20.0, pricing.NextPrice(1000000, desirable, 5, 20))
+}
+
+func TestPricingIsDeterministic(t *testing.T) {
+	for _, curveType := range []string{PricingCurveLinear, PricingCurveExponential, PricingCurvePiecewise} {
+		pricing, err := NewPricingFunction(curveOfType(curveType))
+		require.NoError(t, err)
+
+		price := pricing.NextPrice(1234567, desirable, 1.2345, minPrice)
+		for i := 0; i < 100; i++ {
+			require.Equal(t, price, pricing.NextPrice(1234567, desirable, 1.2345, minPrice))
+		}
+	}
+}
+
+func TestPricingCurveValidation(t *testing.T) {
+	require.NoError(t, DefaultPricingCurve().Validate())
+
+	curve := curveOfType("quadratic")
+	require.Error(t, curve.Validate())
+
+	curve = DefaultPricingCurve()
+	curve.ChangeDenominator = 0
+	require.Error(t, curve.Validate())
+
+	curve = DefaultPricingCurve()
+	curve.Kink = sdk.ZeroDec()
+	require.Error(t, curve.Validate())
+
+	curve = DefaultPricingCurve()
+	curve.KinkMultiplier = sdk.NewDecWithPrec(5, 1)
+	require.Error(t, curve.Validate())
+
+	curve = DefaultPricingCurve()
+	curve.MaxPrice = sdk.Dec{}
+	require.Error(t, curve.Validate())
+}
+
+func TestDecPricingMatchesFloatPricing(t *testing.T) {
+	minDec := sdk.NewDecWithPrec(1, 2)
+	for _, curveType := range []string{PricingCurveLinear, PricingCurveExponential, PricingCurvePiecewise} {
+		pricing, err := NewPricingFunction(curveOfType(curveType))
+		require.NoError(t, err)
+
+		for _, spent := range []uint64{0, 10, 500, 1000, 1500, 3000, 100000} {
+			price := pricing.NextPriceDec(spent, desirable, sdk.NewDec(2), minDec)
+			require.Equal(t, pricing.NextPrice(spent, desirable, 2, minPrice), decToFloat(price), "%s %d", curveType, spent)
+		}
+	}
+}
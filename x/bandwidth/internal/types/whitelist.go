@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const MaxWhitelistedAccounts = 100
+
+// Infrastructure account, such as oracle or relayer, which bandwidth doesn't depend on stake only.
+// Own bandwidth of account is Override if it's set, otherwise stake bandwidth multiplied by Multiplier.
+type WhitelistedAccount struct {
+	Address    sdk.AccAddress `json:"address" yaml:"address"`
+	Override   int64          `json:"override" yaml:"override"`
+	Multiplier sdk.Dec        `json:"multiplier" yaml:"multiplier"`
+}
+
+func NewOverrideWhitelistedAccount(address sdk.AccAddress, override int64) WhitelistedAccount {
+	return WhitelistedAccount{Address: address, Override: override, Multiplier: sdk.ZeroDec()}
+}
+
+func NewMultiplierWhitelistedAccount(address sdk.AccAddress, multiplier sdk.Dec) WhitelistedAccount {
+	return WhitelistedAccount{Address: address, Override: 0, Multiplier: multiplier}
+}
+
+func (a WhitelistedAccount) hasMultiplier() bool {
+	return !a.Multiplier.IsNil() && !a.Multiplier.IsZero()
+}
+
+func (a WhitelistedAccount) OwnBandwidth(stakeBandwidth int64) int64 {
+	if a.Override > 0 {
+		return a.Override
+	}
+	return sdk.NewDec(stakeBandwidth).Mul(a.Multiplier).TruncateInt64()
+}
+
+func (a WhitelistedAccount) Validate() error {
+	if err := sdk.VerifyAddressFormat(a.Address); err != nil {
+		return fmt.Errorf("invalid whitelisted account address: %v", err)
+	}
+	if a.Override < 0 {
+		return fmt.Errorf("bandwidth override must be non-negative: %s", a)
+	}
+	if a.Override > 0 && a.hasMultiplier() {
+		return fmt.Errorf("either bandwidth override or multiplier must be set: %s", a)
+	}
+	if a.Override == 0 && (!a.hasMultiplier() || a.Multiplier.IsNegative()) {
+		return fmt.Errorf("bandwidth override or positive multiplier must be set: %s", a)
+	}
+	return nil
+}
+
+func (a WhitelistedAccount) String() string {
+	if a.Override > 0 {
+		return fmt.Sprintf("%s: override %d", a.Address, a.Override)
+	}
+	return fmt.Sprintf("%s: multiplier %s", a.Address, a.Multiplier)
+}
+
+// Accounts with bandwidth set by governance, their bandwidth still counts against max block bandwidth.
+type BandwidthWhitelist []WhitelistedAccount
+
+func (w BandwidthWhitelist) Get(address sdk.AccAddress) (WhitelistedAccount, bool) {
+	for _, a := range w {
+		if a.Address.Equals(address) {
+			return a, true
+		}
+	}
+	return WhitelistedAccount{}, false
+}
+
+func (w BandwidthWhitelist) Validate() error {
+	if len(w) > MaxWhitelistedAccounts {
+		return fmt.Errorf("too many whitelisted accounts: %d, max %d", len(w), MaxWhitelistedAccounts)
+	}
+	seen := make(map[string]bool)
+	for _, a := range w {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if seen[a.Address.String()] {
+			return fmt.Errorf("duplicate whitelisted account: %s", a.Address)
+		}
+		seen[a.Address.String()] = true
+	}
+	return nil
+}
+
+func (w BandwidthWhitelist) String() string {
+	accounts := make([]string, 0, len(w))
+	for _, a := range w {
+		accounts = append(accounts, a.String())
+	}
+	return strings.Join(accounts, ", ")
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistedAccountBandwidth(t *testing.T) {
+	addr := sdk.AccAddress(make([]byte, sdk.AddrLen))
+
+	require.Equal(t, int64(5000), NewOverrideWhitelistedAccount(addr, 5000).OwnBandwidth(100))
+	require.Equal(t, int64(250), NewMultiplierWhitelistedAccount(addr, sdk.NewDecWithPrec(25, 1)).OwnBandwidth(100))
+	// multiplied stake bandwidth is truncated
+	require.Equal(t, int64(2), NewMultiplierWhitelistedAccount(addr, sdk.NewDecWithPrec(25, 1)).OwnBandwidth(1))
+}
+
+func TestBandwidthWhitelistValidation(t *testing.T) {
+	addr1 := sdk.AccAddress(make([]byte, sdk.AddrLen))
+	addr2 := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 1))
+
+	whitelist := BandwidthWhitelist{
+		NewOverrideWhitelistedAccount(addr1, 5000),
+		NewMultiplierWhitelistedAccount(addr2, sdk.NewDec(2)),
+	}
+	require.NoError(t, whitelist.Validate())
+	whitelisted, ok := whitelist.Get(addr2)
+	require.True(t, ok)
+	require.Equal(t, sdk.NewDec(2), whitelisted.Multiplier)
+
+	require.Error(t, BandwidthWhitelist{whitelist[0], whitelist[0]}.Validate())
+	require.Error(t, BandwidthWhitelist{NewOverrideWhitelistedAccount(addr1, 0)}.Validate())
+	require.Error(t, BandwidthWhitelist{NewOverrideWhitelistedAccount(addr1, -1)}.Validate())
+	require.Error(t, BandwidthWhitelist{NewMultiplierWhitelistedAccount(addr1, sdk.NewDec(-1))}.Validate())
+	require.Error(t, BandwidthWhitelist{NewMultiplierWhitelistedAccount(nil, sdk.NewDec(2))}.Validate())
+
+	both := NewOverrideWhitelistedAccount(addr1, 5000)
+	both.Multiplier = sdk.NewDec(2)
+	require.Error(t, BandwidthWhitelist{both}.Validate())
+}
@@ -0,0 +1,53 @@
+package bandwidth
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cybercongress/go-cyber/x/link"
+)
+
+// Karma accrued to neuron for links of delivered tx
+type KarmaShare struct {
+	Neuron sdk.AccAddress
+	Amount int64
+}
+
+// Splits priced linking cost of tx between neurons of its link msgs in proportion to their links count,
+// so karma accrues to linking neuron, even if other account paid bandwidth. Remainder left by truncation
+// accrues to first neuron, so shares sum up to linking cost.
+func SplitLinkingCost(tx sdk.Tx, linkingCost int64) []KarmaShare {
+	shares := make([]KarmaShare, 0)
+	neuronLinks := make([]int64, 0)
+	totalLinks := int64(0)
+	for _, msg := range tx.GetMsgs() {
+		linkMsg, ok := msg.(link.Msg)
+		if !ok {
+			continue
+		}
+		totalLinks += int64(len(linkMsg.Links))
+
+		found := false
+		for i, share := range shares {
+			if share.Neuron.Equals(linkMsg.Address) {
+				neuronLinks[i] += int64(len(linkMsg.Links))
+				found = true
+				break
+			}
+		}
+		if !found {
+			shares = append(shares, KarmaShare{Neuron: linkMsg.Address})
+			neuronLinks = append(neuronLinks, int64(len(linkMsg.Links)))
+		}
+	}
+	if totalLinks == 0 {
+		return shares
+	}
+
+	distributed := int64(0)
+	for i := range shares {
+		shares[i].Amount = sdk.NewInt(linkingCost).MulRaw(neuronLinks[i]).QuoRaw(totalLinks).Int64()
+		distributed += shares[i].Amount
+	}
+	shares[0].Amount += linkingCost - distributed
+	return shares
+}
@@ -0,0 +1,49 @@
+package bandwidth
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cybercongress/go-cyber/x/link"
+)
+
+func TestSplitLinkingCostOfSponsoredTx(t *testing.T) {
+	payer := sdk.AccAddress([]byte("payer_______________"))
+	neuron1 := sdk.AccAddress([]byte("neuron1_____________"))
+	neuron2 := sdk.AccAddress([]byte("neuron2_____________"))
+
+	links := func(count int) []link.Link {
+		return make([]link.Link, count)
+	}
+	msgs := []sdk.Msg{
+		bank.NewMsgSend(payer, neuron1, sdk.NewCoins(sdk.NewInt64Coin("eul", 1))),
+		link.NewMsg(neuron1, links(1)),
+		link.NewMsg(neuron2, links(1)),
+		link.NewMsg(neuron1, links(1)),
+	}
+	tx := auth.NewStdTx(msgs, auth.StdFee{}, nil, BandwidthPayerMemoPrefix+payer.String())
+	require.Equal(t, []sdk.AccAddress{payer, neuron1, neuron2}, tx.GetSigners())
+
+	txPayer, err := GetBandwidthPayer(tx)
+	require.NoError(t, err)
+	require.Equal(t, payer, txPayer)
+
+	// payer signed first, but karma accrues to linking neurons only, remainder to the first of them
+	shares := SplitLinkingCost(tx, 100)
+	require.Equal(t, []KarmaShare{
+		{Neuron: neuron1, Amount: 67},
+		{Neuron: neuron2, Amount: 33},
+	}, shares)
+}
+
+func TestSponsoredBandwidthUpgrade(t *testing.T) {
+	input := createTestInput(t, 200)
+
+	require.False(t, input.accKeeper.IsSponsoredBandwidthEnabled(input.ctx))
+	input.accKeeper.EnableSponsoredBandwidth(input.ctx)
+	require.True(t, input.accKeeper.IsSponsoredBandwidthEnabled(input.ctx))
+}
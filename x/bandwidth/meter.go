@@ -24,11 +24,17 @@ type BaseBandwidthMeter struct {
 	// price adjustment fields
 	curBlockSpentBandwidth     uint64 //resets every block
 	currentCreditPrice         float64
+	currentCreditPriceDec      sdk.Dec           // used after fixed point math upgrade
 	bandwidthSpent             map[uint64]uint64 // bandwidth spent by blocks
 	totalSpentForSlidingWindow uint64
 	currentBlockSpentKarma     uint64
+	recoveryPeriod             int64 // sliding window length
+	fixedPointMath             bool
 }
 
+// max blocks values deleted from block spent bandwidth store per block
+const maxPrunedBlocksPerCommit = 1000
+
 func NewBaseMeter(
 	mk store.MainKeeper, ak auth.AccountKeeper, bwk AccountBandwidthKeeper,
 	bbwk BlockSpentBandwidthKeeper, sp types.AccStakeProvider, msgCost types.MsgBandwidthCost,
@@ -47,17 +53,70 @@ func NewBaseMeter(
 
 func (m *BaseBandwidthMeter) Load(ctx sdk.Context) {
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
-	m.totalSpentForSlidingWindow = 0
-	m.bandwidthSpent = m.blockBandwidthKeeper.GetValuesForPeriod(ctx, params.RecoveryPeriod)
-	for _, spentBandwidth := range m.bandwidthSpent {
-		m.totalSpentForSlidingWindow += spentBandwidth
+	m.loadWindow(ctx, params.RecoveryPeriod)
+	m.fixedPointMath = m.accountBaindwidthKeeper.IsFixedPointMathEnabled(ctx)
+	if m.fixedPointMath {
+		m.setCreditPriceDec(m.mainKeeper.GetBandwidthPriceDec(ctx, minCreditPrice(params)))
+	} else {
+		floatBaseCreditPrice, err := strconv.ParseFloat(params.BaseCreditPrice.String(), 64)
+		if err != nil {
+			panic(err)
+		}
+		m.currentCreditPrice = 0.01 * math.Float64frombits(m.mainKeeper.GetBandwidthPrice(ctx, floatBaseCreditPrice))
 	}
-	floatBaseCreditPrice, err := strconv.ParseFloat(params.BaseCreditPrice.String(), 64)
+	m.curBlockSpentBandwidth = 0
+}
+
+// Converts stored float price to decimal, so stake bandwidth, accounts recovery, priced costs and price
+// are calculated with fixed point arithmetic afterwards. Done by upgrade, so state of blocks before
+// upgrade is not changed. Price is loaded from store, the same on restarted and running nodes.
+func (m *BaseBandwidthMeter) EnableFixedPointMath(ctx sdk.Context) error {
+	params := m.accountBaindwidthKeeper.GetParams(ctx)
+	minPrice := minCreditPrice(params)
+
+	if !m.accountBaindwidthKeeper.IsFixedPointMathEnabled(ctx) {
+		floatMinPrice, err := types.DecToFloat(minPrice)
+		if err != nil {
+			return err
+		}
+		price, err := types.FloatToDec(math.Float64frombits(m.mainKeeper.GetBandwidthPrice(ctx, floatMinPrice)))
+		if err != nil {
+			return err
+		}
+		if price.LT(minPrice) {
+			price = minPrice
+		}
+		m.mainKeeper.StoreBandwidthPriceDec(ctx, price)
+		m.accountBaindwidthKeeper.EnableFixedPointMath(ctx)
+	}
+
+	m.fixedPointMath = true
+	m.setCreditPriceDec(m.mainKeeper.GetBandwidthPriceDec(ctx, minPrice))
+	return nil
+}
+
+// float price is kept for rpc and events
+func (m *BaseBandwidthMeter) setCreditPriceDec(price sdk.Dec) {
+	floatPrice, err := types.DecToFloat(price)
 	if err != nil {
 		panic(err)
 	}
-	m.currentCreditPrice = 0.01 * math.Float64frombits(m.mainKeeper.GetBandwidthPrice(ctx, floatBaseCreditPrice))
-	m.curBlockSpentBandwidth = 0
+	m.currentCreditPriceDec = price
+	m.currentCreditPrice = floatPrice
+}
+
+func minCreditPrice(params types.Params) sdk.Dec {
+	return params.BaseCreditPrice.Mul(sdk.NewDecWithPrec(1, 2))
+}
+
+// loads spent bandwidth of recovery period blocks up to context block
+func (m *BaseBandwidthMeter) loadWindow(ctx sdk.Context, recoveryPeriod int64) {
+	m.totalSpentForSlidingWindow = 0
+	m.bandwidthSpent = m.blockBandwidthKeeper.GetValuesForPeriod(ctx, recoveryPeriod)
+	for _, spentBandwidth := range m.bandwidthSpent {
+		m.totalSpentForSlidingWindow += spentBandwidth
+	}
+	m.recoveryPeriod = recoveryPeriod
 }
 
 func (m *BaseBandwidthMeter) AddToBlockBandwidth(value int64) {
@@ -71,16 +130,22 @@ func (m *BaseBandwidthMeter) AddToBlockKarma(value int64) {
 // Here we move bandwidth window:
 // Remove first block of window and add new block to window end
 func (m *BaseBandwidthMeter) CommitBlockBandwidth(ctx sdk.Context) {
+	params := m.accountBaindwidthKeeper.GetParams(ctx)
+	// Recovery period changed via governance, window of previous blocks is loaded again. Before pruning
+	// upgrade window is not reloaded, as values of blocks removed from window are not pruned then.
+	// Values of blocks out of previous window are pruned already, so on recovery period increase
+	// window is extended by new blocks gradually, till it reaches new recovery period length.
+	if params.RecoveryPeriod != m.recoveryPeriod && m.blockBandwidthKeeper.IsPruningEnabled(ctx) {
+		m.loadWindow(ctx.WithBlockHeight(ctx.BlockHeight()-1), params.RecoveryPeriod)
+	}
+
 	m.totalSpentForSlidingWindow += m.curBlockSpentBandwidth
 
 	newWindowEnd := ctx.BlockHeight()
-	params := m.accountBaindwidthKeeper.GetParams(ctx)
 	windowStart := newWindowEnd - params.RecoveryPeriod
 	if windowStart < 0 { // check needed cause it will be casted to uint and can cause overflow
 		windowStart = 0
 	}
-	// If recovery period will be increased via governance extended windows will not be accessible
-	// todo If recover period will be decreased via governance need to clean garbage values
 	windowStartValue, exists := m.bandwidthSpent[uint64(windowStart)]
 	if exists {
 		m.totalSpentForSlidingWindow -= windowStartValue
@@ -89,6 +154,11 @@ func (m *BaseBandwidthMeter) CommitBlockBandwidth(ctx sdk.Context) {
 	m.blockBandwidthKeeper.SetBlockSpentBandwidth(ctx, uint64(ctx.BlockHeight()), m.curBlockSpentBandwidth)
 	m.bandwidthSpent[uint64(newWindowEnd)] = m.curBlockSpentBandwidth
 	m.curBlockSpentBandwidth = 0
+
+	// values of blocks out of window are not needed anymore
+	if windowStart > 0 {
+		m.blockBandwidthKeeper.Prune(ctx, uint64(windowStart+1), maxPrunedBlocksPerCommit)
+	}
 }
 
 func (m *BaseBandwidthMeter) CommitTotalKarma(ctx sdk.Context) {
@@ -101,25 +171,59 @@ func (m *BaseBandwidthMeter) CommitTotalKarma(ctx sdk.Context) {
 
 func (m *BaseBandwidthMeter) AdjustPrice(ctx sdk.Context) {
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
-	floatBaseCreditPrice, err := strconv.ParseFloat(params.BaseCreditPrice.String(), 64)
+	pricing, err := types.NewPricingFunction(params.PricingCurve)
 	if err != nil {
 		panic(err)
 	}
-	newPrice := float64(m.totalSpentForSlidingWindow) / float64(params.DesirableBandwidth)
 
-	if newPrice < 0.01 * floatBaseCreditPrice {
-		newPrice = 0.01 * floatBaseCreditPrice
+	var newPrice, load float64
+	if m.fixedPointMath {
+		newPrice, load = m.adjustPriceFixedPoint(ctx, params, pricing)
+	} else {
+		floatBaseCreditPrice, err := strconv.ParseFloat(params.BaseCreditPrice.String(), 64)
+		if err != nil {
+			panic(err)
+		}
+		minPrice := 0.01 * floatBaseCreditPrice
+		currentPrice := math.Float64frombits(m.mainKeeper.GetBandwidthPrice(ctx, minPrice))
+		newPrice = pricing.NextPrice(m.totalSpentForSlidingWindow, params.DesirableBandwidth, currentPrice, minPrice)
+		load = float64(m.totalSpentForSlidingWindow) / float64(params.DesirableBandwidth)
+
+		m.currentCreditPrice = newPrice
+		m.mainKeeper.StoreBandwidthPrice(ctx, math.Float64bits(newPrice))
+	}
+
+	if params.PriceHistoryRetention > 0 {
+		m.mainKeeper.StoreBandwidthPriceRecord(ctx, ctx.BlockHeight(), math.Float64bits(newPrice), math.Float64bits(load))
+		m.mainKeeper.PruneBandwidthPriceRecords(ctx, ctx.BlockHeight()-params.PriceHistoryRetention)
 	}
 
-	m.currentCreditPrice = newPrice
-	m.mainKeeper.StoreBandwidthPrice(ctx, math.Float64bits(newPrice))
+	ctx.EventManager().EmitEvent(types.NewBandwidthPriceEvent(newPrice, load))
+}
+
+// stores decimal price, returns price and load converted to floats for history and events
+func (m *BaseBandwidthMeter) adjustPriceFixedPoint(
+	ctx sdk.Context, params types.Params, pricing types.PricingFunction,
+) (float64, float64) {
+	minPrice := minCreditPrice(params)
+	currentPrice := m.mainKeeper.GetBandwidthPriceDec(ctx, minPrice)
+	newPrice := pricing.NextPriceDec(m.totalSpentForSlidingWindow, params.DesirableBandwidth, currentPrice, minPrice)
+
+	m.setCreditPriceDec(newPrice)
+	m.mainKeeper.StoreBandwidthPriceDec(ctx, newPrice)
+
+	load, err := types.DecToFloat(types.NetworkLoad(m.totalSpentForSlidingWindow, params.DesirableBandwidth))
+	if err != nil {
+		panic(err)
+	}
+	return m.currentCreditPrice, load
 }
 
 func (m *BaseBandwidthMeter) GetTxCost(ctx sdk.Context, tx sdk.Tx) int64 {
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
 	bandwidthForTx := params.TxCost
 	for _, msg := range tx.GetMsgs() {
-		bandwidthForTx = bandwidthForTx + m.msgCost(ctx, params, msg)
+		bandwidthForTx = types.AddCosts(bandwidthForTx, m.msgCost(ctx, params, msg))
 	}
 	return bandwidthForTx
 }
@@ -131,7 +235,7 @@ func (m *BaseBandwidthMeter) GetMaxBlockBandwidth(ctx sdk.Context) uint64 {
 }
 
 func (m *BaseBandwidthMeter) GetPricedTxCost(ctx sdk.Context, tx sdk.Tx) int64 {
-	return int64(float64(m.GetTxCost(ctx, tx)) * m.currentCreditPrice)
+	return m.priceCost(m.GetTxCost(ctx, tx))
 }
 
 func (m *BaseBandwidthMeter) GetCurBlockSpentBandwidth(ctx sdk.Context) uint64 {
@@ -143,29 +247,80 @@ func (m *BaseBandwidthMeter) GetPricedLinksCost(ctx sdk.Context, tx sdk.Tx) int6
 	usedBandwidth := int64(0)
 	for _, msg := range tx.GetMsgs() {
 		if msg.Type() == "link" {
-			usedBandwidth = usedBandwidth + m.msgCost(ctx, params, msg)
+			usedBandwidth = types.AddCosts(usedBandwidth, m.msgCost(ctx, params, msg))
 		}
 	}
-	return int64(float64(usedBandwidth) * m.currentCreditPrice)
+	return m.priceCost(usedBandwidth)
 }
 
+// Priced cost is capped by math.MaxInt64 instead of overflowing.
+func (m *BaseBandwidthMeter) priceCost(cost int64) int64 {
+	if m.fixedPointMath {
+		priced := sdk.NewDec(cost).Mul(m.currentCreditPriceDec).TruncateInt()
+		if !priced.IsInt64() {
+			return math.MaxInt64
+		}
+		return priced.Int64()
+	}
+	priced := float64(cost) * m.currentCreditPrice
+	if priced >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(priced)
+}
+
+// Own bandwidth of account stake without granted part, plus bandwidth granted to account by others.
+// Own bandwidth of whitelisted account is set by whitelist, others could be granted only stake bandwidth of it.
 func (m *BaseBandwidthMeter) GetAccMaxBandwidth(ctx sdk.Context, addr sdk.AccAddress) int64 {
-	accStakePercentage := m.stakeProvider.GetAccStakePercentage(ctx, addr)
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
+	ownBandwidth := m.getAccStakeBandwidth(ctx, addr, params)
+	if whitelisted, ok := params.Whitelist.Get(addr); ok {
+		ownBandwidth = whitelisted.OwnBandwidth(ownBandwidth)
+	}
+
+	outgoingFraction := types.ActiveGrantsFraction(m.accountBaindwidthKeeper.GetOutgoingGrants(ctx, addr), ctx.BlockHeight())
+	maxBandwidth := ownBandwidth - sdk.NewDec(ownBandwidth).Mul(outgoingFraction).TruncateInt64()
+
+	for _, grant := range m.accountBaindwidthKeeper.GetIncomingGrants(ctx, addr) {
+		if !grant.IsActive(ctx.BlockHeight()) {
+			continue
+		}
+		granterBandwidth := m.getAccStakeBandwidth(ctx, grant.Granter, params)
+		maxBandwidth += sdk.NewDec(granterBandwidth).Mul(grant.Fraction).TruncateInt64()
+	}
+	return maxBandwidth
+}
+
+func (m *BaseBandwidthMeter) getAccStakeBandwidth(ctx sdk.Context, addr sdk.AccAddress, params types.Params) int64 {
+	if m.fixedPointMath {
+		totalSupply := m.stakeProvider.GetTotalSupply(ctx)
+		if totalSupply <= 0 {
+			return 0
+		}
+		accStake := m.stakeProvider.GetAccountTotalStake(ctx, addr)
+		return sdk.NewInt(accStake).MulRaw(params.DesirableBandwidth).QuoRaw(totalSupply).Int64()
+	}
+	accStakePercentage := m.stakeProvider.GetAccStakePercentage(ctx, addr)
 	return int64(accStakePercentage * float64(params.DesirableBandwidth))
 }
 
-func (m *BaseBandwidthMeter) GetCurrentAccBandwidth(ctx sdk.Context, address sdk.AccAddress) types.AcсountBandwidth {
-	accBw := m.accountBaindwidthKeeper.GetAccountBandwidth(ctx, address)
+func (m *BaseBandwidthMeter) GetCurrentAccBandwidth(ctx sdk.Context, address sdk.AccAddress) (types.AccountBandwidth, error) {
+	accBw, err := m.accountBaindwidthKeeper.GetAccountBandwidth(ctx, address)
+	if err != nil {
+		return accBw, err
+	}
 	accMaxBw := m.GetAccMaxBandwidth(ctx, address)
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
-	accBw.UpdateMax(accMaxBw, ctx.BlockHeight(), params.RecoveryPeriod)
-	return accBw
+	accBw.UpdateMax(accMaxBw, ctx.BlockHeight(), params.RecoveryPeriod, m.fixedPointMath)
+	return accBw, nil
 }
 
-func (m *BaseBandwidthMeter) UpdateAccMaxBandwidth(ctx sdk.Context, address sdk.AccAddress) {
-	bw := m.GetCurrentAccBandwidth(ctx, address)
-	m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw)
+func (m *BaseBandwidthMeter) UpdateAccMaxBandwidth(ctx sdk.Context, address sdk.AccAddress) error {
+	bw, err := m.GetCurrentAccBandwidth(ctx, address)
+	if err != nil {
+		return err
+	}
+	return m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw)
 }
 
 //
@@ -176,19 +331,23 @@ func (m *BaseBandwidthMeter) UpdateAccMaxBandwidth(ctx sdk.Context, address sdk.
 // bw := getCurrentBw(addr)
 // bwCost := deliverTx(tx)
 // consumeBw(bw, bwCost)
-func (m *BaseBandwidthMeter) ConsumeAccBandwidth(ctx sdk.Context, bw types.AcсountBandwidth, amt int64) {
+func (m *BaseBandwidthMeter) ConsumeAccBandwidth(ctx sdk.Context, bw types.AccountBandwidth, amt int64) error {
 	bw.Consume(amt)
-	m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw)
-	bw = m.GetCurrentAccBandwidth(ctx, bw.Address)
-	m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw)
+	if err := m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw); err != nil {
+		return err
+	}
+	return m.UpdateAccMaxBandwidth(ctx, bw.Address)
 }
 
-func (m *BaseBandwidthMeter) UpdateLinkedBandwidth(ctx sdk.Context, bw types.AcсountBandwidth, amt int64) {
+func (m *BaseBandwidthMeter) UpdateLinkedBandwidth(ctx sdk.Context, bw types.AccountBandwidth, amt int64) error {
 	bw.AddLinked(amt)
-	m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw)
+	if err := m.accountBaindwidthKeeper.SetAccountBandwidth(ctx, bw); err != nil {
+		return err
+	}
+	ctx.EventManager().EmitEvent(types.NewKarmaEvent(bw.Address, amt, bw.Linked))
+	return nil
 }
 
-
 func (m *BaseBandwidthMeter) GetCurrentCreditPrice() float64 {
 	return m.currentCreditPrice
 }
@@ -197,3 +356,18 @@ func (m *BaseBandwidthMeter) GetCurrentNetworkLoad(ctx sdk.Context) float64 {
 	params := m.accountBaindwidthKeeper.GetParams(ctx)
 	return float64(m.totalSpentForSlidingWindow) / float64(params.DesirableBandwidth)
 }
+
+func (m *BaseBandwidthMeter) GetPriceHistory(
+	ctx sdk.Context, fromHeight int64, toHeight int64, limit int,
+) []types.PriceRecord {
+	records := make([]types.PriceRecord, 0)
+	m.mainKeeper.IterateBandwidthPriceRecords(ctx, fromHeight, toHeight, func(height int64, price uint64, load uint64) bool {
+		records = append(records, types.PriceRecord{
+			Height: height,
+			Price:  math.Float64frombits(price),
+			Load:   math.Float64frombits(load),
+		})
+		return len(records) >= limit
+	})
+	return records
+}
@@ -0,0 +1,271 @@
+package bandwidth
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cybercongress/go-cyber/store"
+)
+
+type testInput struct {
+	ctx         sdk.Context
+	accKey      sdk.StoreKey
+	blockKey    sdk.StoreKey
+	accKeeper   AccountBandwidthKeeper
+	blockKeeper BlockSpentBandwidthKeeper
+	mainKeeper  store.MainKeeper
+}
+
+func createTestInput(t *testing.T, recoveryPeriod int64) testInput {
+	mainKey := sdk.NewKVStoreKey("main")
+	accKey := sdk.NewKVStoreKey("acc_bandwidth")
+	blockKey := sdk.NewKVStoreKey("block_spent_bandwidth")
+	moduleKey := sdk.NewKVStoreKey(StoreKey)
+	paramsKey := sdk.NewKVStoreKey(params.StoreKey)
+	tParamsKey := sdk.NewTransientStoreKey(params.TStoreKey)
+
+	db := dbm.NewMemDB()
+	ms := sdkstore.NewCommitMultiStore(db)
+	for _, key := range []sdk.StoreKey{mainKey, accKey, blockKey, moduleKey, paramsKey} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
+	ms.MountStoreWithDB(tParamsKey, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	cdc := codec.New()
+	paramsKeeper := params.NewKeeper(cdc, paramsKey, tParamsKey)
+
+	input := testInput{
+		ctx:         ctx,
+		accKey:      accKey,
+		blockKey:    blockKey,
+		accKeeper:   NewAccountBandwidthKeeper(cdc, accKey, moduleKey, paramsKeeper.Subspace(DefaultParamspace)),
+		blockKeeper: NewBlockSpentBandwidthKeeper(blockKey),
+		mainKeeper:  store.NewMainKeeper(mainKey),
+	}
+	input.setRecoveryPeriod(recoveryPeriod)
+	input.blockKeeper.EnablePruning(ctx)
+	return input
+}
+
+func (input testInput) newMeter() *BaseBandwidthMeter {
+	return NewBaseMeter(
+		input.mainKeeper, auth.AccountKeeper{}, input.accKeeper, input.blockKeeper, nil, MsgBandwidthCosts,
+	)
+}
+
+func (input testInput) setRecoveryPeriod(recoveryPeriod int64) {
+	params := DefaultParams()
+	params.RecoveryPeriod = recoveryPeriod
+	input.accKeeper.SetParams(input.ctx, params)
+}
+
+// each block spends bandwidth equal to its height
+func (input testInput) commitBlocks(meter *BaseBandwidthMeter, from, to int64) {
+	for height := from; height <= to; height++ {
+		meter.AddToBlockBandwidth(height)
+		meter.CommitBlockBandwidth(input.ctx.WithBlockHeight(height))
+	}
+}
+
+func (input testInput) hasBlockValue(height int64) bool {
+	key := make([]byte, 8)
+	binary.LittleEndian.PutUint64(key, uint64(height))
+	return input.ctx.KVStore(input.blockKey).Has(key)
+}
+
+// spent bandwidth of blocks from..to
+func spentSum(from, to int64) uint64 {
+	sum := uint64(0)
+	for height := from; height <= to; height++ {
+		sum += uint64(height)
+	}
+	return sum
+}
+
+func requireWindow(t *testing.T, input testInput, meter *BaseBandwidthMeter, height int64, total uint64) {
+	require.Equal(t, total, meter.totalSpentForSlidingWindow)
+
+	// the same window is loaded after restart
+	restarted := input.newMeter()
+	restarted.Load(input.ctx.WithBlockHeight(height))
+	require.Equal(t, total, restarted.totalSpentForSlidingWindow)
+}
+
+func TestBlockSpentBandwidthPruning(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+	meter.Load(input.ctx)
+
+	input.commitBlocks(meter, 1, 500)
+	requireWindow(t, input, meter, 500, spentSum(301, 500))
+
+	// values of blocks out of window are pruned
+	for height := int64(1); height <= 300; height++ {
+		require.False(t, input.hasBlockValue(height), "block %d", height)
+	}
+	for height := int64(301); height <= 500; height++ {
+		require.True(t, input.hasBlockValue(height), "block %d", height)
+	}
+}
+
+func TestRecoveryPeriodDecrease(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+	meter.Load(input.ctx)
+
+	input.commitBlocks(meter, 1, 500)
+	input.setRecoveryPeriod(150)
+
+	input.commitBlocks(meter, 501, 501)
+	requireWindow(t, input, meter, 501, spentSum(352, 501))
+	require.False(t, input.hasBlockValue(351))
+	require.True(t, input.hasBlockValue(352))
+
+	input.commitBlocks(meter, 502, 700)
+	requireWindow(t, input, meter, 700, spentSum(551, 700))
+	require.False(t, input.hasBlockValue(550))
+	require.True(t, input.hasBlockValue(551))
+}
+
+func TestRecoveryPeriodIncrease(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+	meter.Load(input.ctx)
+
+	input.commitBlocks(meter, 1, 500)
+	input.setRecoveryPeriod(300)
+
+	// values of blocks out of previous window are pruned, window is extended by new blocks
+	input.commitBlocks(meter, 501, 501)
+	requireWindow(t, input, meter, 501, spentSum(301, 501))
+
+	input.commitBlocks(meter, 502, 600)
+	requireWindow(t, input, meter, 600, spentSum(301, 600))
+
+	input.commitBlocks(meter, 601, 700)
+	requireWindow(t, input, meter, 700, spentSum(401, 700))
+	require.False(t, input.hasBlockValue(400))
+	require.True(t, input.hasBlockValue(401))
+}
+
+func TestRecoveryPeriodIncreaseWithoutPruning(t *testing.T) {
+	input := createTestInput(t, 200)
+	meter := input.newMeter()
+	meter.Load(input.ctx)
+
+	// window is extended by blocks values not pruned yet
+	input.commitBlocks(meter, 1, 150)
+	input.setRecoveryPeriod(300)
+
+	input.commitBlocks(meter, 151, 151)
+	requireWindow(t, input, meter, 151, spentSum(1, 151))
+
+	input.commitBlocks(meter, 152, 400)
+	requireWindow(t, input, meter, 400, spentSum(101, 400))
+}
+
+func TestAccountBandwidthEncodingMigration(t *testing.T) {
+	input := createTestInput(t, 200)
+	accStore := input.ctx.KVStore(input.accKey)
+
+	addr := sdk.AccAddress(make([]byte, sdk.AddrLen))
+	bw := AccountBandwidth{Address: addr, RemainedValue: 10, LastUpdatedBlock: 5, MaxValue: 20, Linked: 3}
+	require.NoError(t, input.accKeeper.SetAccountBandwidth(input.ctx, bw))
+	require.Equal(t, byte('{'), accStore.Get(addr)[0])
+
+	require.NoError(t, input.accKeeper.MigrateAccountsBandwidthEncoding(input.ctx))
+	require.Equal(t, byte(1), accStore.Get(addr)[0])
+	migrated, err := input.accKeeper.GetAccountBandwidth(input.ctx, addr)
+	require.NoError(t, err)
+	require.Equal(t, bw, migrated)
+
+	accStore.Set(addr, []byte{0xff})
+	_, err = input.accKeeper.GetAccountBandwidth(input.ctx, addr)
+	require.Error(t, err)
+}
+
+// accounts stakes of total supply
+type testStakeProvider map[string]int64
+
+const testTotalSupply = int64(1000000)
+
+func (p testStakeProvider) GetAccStakePercentage(_ sdk.Context, address sdk.AccAddress) float64 {
+	return float64(p[address.String()]) / float64(testTotalSupply)
+}
+
+func (p testStakeProvider) GetAccountTotalStake(_ sdk.Context, address sdk.AccAddress) int64 {
+	return p[address.String()]
+}
+
+func (p testStakeProvider) GetTotalSupply(_ sdk.Context) int64 {
+	return testTotalSupply
+}
+
+func TestWhitelistedAccMaxBandwidth(t *testing.T) {
+	input := createTestInput(t, 200)
+	oracle := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 1))
+	relayer := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 2))
+	neuron := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 3))
+
+	params := DefaultParams()
+	params.RecoveryPeriod = 200
+	params.Whitelist = BandwidthWhitelist{
+		NewOverrideWhitelistedAccount(oracle, 5000),
+		NewMultiplierWhitelistedAccount(relayer, sdk.NewDec(3)),
+	}
+	input.accKeeper.SetParams(input.ctx, params)
+
+	stake := testStakeProvider{relayer.String(): 1, neuron.String(): 1}
+	meter := NewBaseMeter(
+		input.mainKeeper, auth.AccountKeeper{}, input.accKeeper, input.blockKeeper, stake, MsgBandwidthCosts,
+	)
+
+	require.Equal(t, int64(5000), meter.GetAccMaxBandwidth(input.ctx, oracle))
+	require.Equal(t, int64(6000), meter.GetAccMaxBandwidth(input.ctx, relayer))
+	require.Equal(t, int64(2000), meter.GetAccMaxBandwidth(input.ctx, neuron))
+}
+
+func TestEnableFixedPointMath(t *testing.T) {
+	input := createTestInput(t, 200)
+	neuron := sdk.AccAddress(append(make([]byte, sdk.AddrLen-1), 1))
+	stake := testStakeProvider{neuron.String(): 3}
+	meter := NewBaseMeter(
+		input.mainKeeper, auth.AccountKeeper{}, input.accKeeper, input.blockKeeper, stake, MsgBandwidthCosts,
+	)
+	meter.Load(input.ctx)
+
+	input.mainKeeper.StoreBandwidthPrice(input.ctx, math.Float64bits(0.75))
+	require.NoError(t, meter.EnableFixedPointMath(input.ctx))
+	require.True(t, input.accKeeper.IsFixedPointMathEnabled(input.ctx))
+	require.Equal(t, sdk.NewDecWithPrec(75, 2), input.mainKeeper.GetBandwidthPriceDec(input.ctx, sdk.OneDec()))
+	require.Equal(t, 0.75, meter.GetCurrentCreditPrice())
+	require.Equal(t, int64(749), meter.priceCost(999))
+
+	// 3 * 2000000000 / 1000000
+	require.Equal(t, int64(6000), meter.GetAccMaxBandwidth(input.ctx, neuron))
+
+	// price is stored as decimal
+	input.commitBlocks(meter, 1, 10)
+	meter.AdjustPrice(input.ctx.WithBlockHeight(10))
+	require.Equal(t, sdk.NewDecWithPrec(1, 2), input.mainKeeper.GetBandwidthPriceDec(input.ctx, sdk.OneDec()))
+	require.Equal(t, math.Float64bits(0.75), input.mainKeeper.GetBandwidthPrice(input.ctx, 1))
+
+	// restarted meter uses stored decimal price
+	restarted := input.newMeter()
+	restarted.Load(input.ctx.WithBlockHeight(10))
+	require.Equal(t, 0.01, restarted.GetCurrentCreditPrice())
+	require.Equal(t, int64(9), restarted.priceCost(999))
+}
@@ -31,7 +31,7 @@ func (AppModuleBasic) Name() string {
 	return ModuleName
 }
 
-func (AppModuleBasic) RegisterCodec(cdc *codec.Codec) {}
+func (AppModuleBasic) RegisterCodec(cdc *codec.Codec) { RegisterCodec(cdc) }
 
 func (AppModuleBasic) DefaultGenesis() json.RawMessage {
 	return ModuleCdc.MustMarshalJSON(DefaultGenesisState())
@@ -50,7 +50,9 @@ func (AppModuleBasic) RegisterRESTRoutes(ctx context.CLIContext, rtr *mux.Router
 	rest.RegisterRoutes(ctx, rtr)
 }
 
-func (AppModuleBasic) GetTxCmd(_ *codec.Codec) *cobra.Command { return nil }
+func (AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command {
+	return cli.GetTxCmd(cdc)
+}
 
 // get the root query command of this module
 func (AppModuleBasic) GetQueryCmd(cdc *codec.Codec) *cobra.Command {
@@ -62,16 +64,19 @@ type AppModule struct {
 
 	AccountBandwidthKeeper    AccountBandwidthKeeper
 	BlockSpentBandwidthKeeper BlockSpentBandwidthKeeper
+	Meter                     Meter
 }
 
 func NewAppModule(
 	accountBandwidthKeeper AccountBandwidthKeeper,
 	blockSpentBandwidthKeeper BlockSpentBandwidthKeeper,
+	meter Meter,
 ) AppModule {
 	return AppModule{
 		AppModuleBasic:            AppModuleBasic{},
 		AccountBandwidthKeeper:    accountBandwidthKeeper,
 		BlockSpentBandwidthKeeper: blockSpentBandwidthKeeper,
+		Meter:                     meter,
 	}
 }
 
@@ -81,16 +86,18 @@ func (AppModule) Name() string {
 
 func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {}
 
-func (am AppModule) Route() string           { return "" }
+func (am AppModule) Route() string { return RouterKey }
 
-func (am AppModule) NewHandler() sdk.Handler { return nil }
+func (am AppModule) NewHandler() sdk.Handler {
+	return NewHandler(am.AccountBandwidthKeeper, am.Meter)
+}
 
 func (am AppModule) QuerierRoute() string {
 	return QuerierRoute
 }
 
 func (am AppModule) NewQuerierHandler() sdk.Querier {
-	return NewQuerier(am.AccountBandwidthKeeper)
+	return NewQuerier(am.AccountBandwidthKeeper, am.Meter)
 }
 
 func (am AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {}
@@ -107,6 +114,6 @@ func (am AppModule) InitGenesis(ctx sdk.Context, data json.RawMessage) []abci.Va
 }
 
 func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
-	gs := ExportGenesis(ctx, am.AccountBandwidthKeeper)
+	gs := ExportGenesis(ctx, am.AccountBandwidthKeeper, am.BlockSpentBandwidthKeeper)
 	return ModuleCdc.MustMarshalJSON(gs)
 }
@@ -0,0 +1,39 @@
+package bandwidth
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+// Memo field designating account which bandwidth is consumed by tx instead of first signer,
+// e.g. "bandwidth_payer:cyber1...". Payer should sign tx, so it should be signer of one of tx msgs.
+// Memo is respected after SponsoredBandwidthUpgrade only.
+const BandwidthPayerMemoPrefix = "bandwidth_payer:"
+
+// Returns account which bandwidth is consumed by tx. It is first signer, unless other
+// signer is designated as payer in memo.
+func GetBandwidthPayer(tx auth.StdTx) (sdk.AccAddress, error) {
+	signers := tx.GetSigners()
+
+	for _, field := range strings.Fields(tx.GetMemo()) {
+		if !strings.HasPrefix(field, BandwidthPayerMemoPrefix) {
+			continue
+		}
+
+		payer, err := sdk.AccAddressFromBech32(strings.TrimPrefix(field, BandwidthPayerMemoPrefix))
+		if err != nil {
+			return nil, sdkerrors.Wrap(ErrInvalidBandwidthPayer, err.Error())
+		}
+		for _, signer := range signers {
+			if signer.Equals(payer) {
+				return payer, nil
+			}
+		}
+		return nil, sdkerrors.Wrapf(ErrInvalidBandwidthPayer, "%s is not tx signer", payer)
+	}
+
+	return signers[0], nil
+}
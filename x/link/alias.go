@@ -10,6 +10,9 @@ const (
 	ModuleName = types.ModuleName
 	StoreKey   = types.StoreKey
 	RouterKey  = types.RouterKey
+
+	CidStoreKey        = types.CidStoreKey
+	CidReverseStoreKey = types.CidReverseStoreKey
 )
 
 var (
@@ -17,6 +20,7 @@ var (
 	NewLinkKeeper      = keeper.NewLinkKeeper
 	NewIndexedKeeper   = keeper.NewIndexedKeeper
 	NewCidNumberKeeper = keeper.NewCidNumberKeeper
+	RegisterInvariants = keeper.RegisterInvariants
 
 	// types
 	RegisterCodec = types.RegisterCodec
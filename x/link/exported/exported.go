@@ -15,8 +15,10 @@ type KeeperI interface {
 	GetLinksCount(sdk.Context) uint64
 	IterateLinks(sdk.Context, func(types.CompactLink))
 	IterateBinaryLinks(sdk.Context, func([]byte))
+	IterateLinksWithHeight(sdk.Context, func(types.CompactLink, uint64))
 
 	PutLink(sdk.Context, types.CompactLink)
+	PutLinkAtHeight(sdk.Context, types.CompactLink, uint64)
 	WriteLinks(sdk.Context, io.Writer) error
 
 	Commit(ctx sdk.Context)
@@ -27,10 +29,13 @@ type IndexedKeeperI interface {
 
 	Load(rankCtx sdk.Context, freshCtx sdk.Context)
 	FixLinks()
-	EndBlocker() bool
+	EndBlocker(sdk.Context) bool
 
 	PutIntoIndex(types.CompactLink)
 
+	BeginTx()
+	DiscardTxLinks()
+
 	GetOutLinks() types.Links
 	GetInLinks() types.Links
 
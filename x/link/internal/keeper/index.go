@@ -7,7 +7,9 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	tmos "github.com/tendermint/tendermint/libs/os"
 
+	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 )
 
@@ -23,10 +25,19 @@ type IndexedKeeper struct {
 	nextRankOutLinks types.Links
 
 	currentBlockLinks []types.CompactLink
+	// heights of current block links created before (imported at genesis)
+	currentBlockLinksHeights map[types.CompactLink]uint64
+
+	// positions of links of delivering tx in block links and buffer
+	txBlockLinksStart int
+	txBufferStart     int
 }
 
 func NewIndexedKeeper(keeper *Keeper) *IndexedKeeper {
-	return &IndexedKeeper{Keeper: keeper}
+	return &IndexedKeeper{
+		Keeper:                   keeper,
+		currentBlockLinksHeights: make(map[types.CompactLink]uint64),
+	}
 }
 
 func (i *IndexedKeeper) Load(rankCtx sdk.Context, freshCtx sdk.Context) {
@@ -58,13 +69,20 @@ func (i *IndexedKeeper) FixLinks() {
 }
 
 // return true if this block has new links
-func (i *IndexedKeeper) EndBlocker() bool {
+// block links are committed to store at the same height
+func (i *IndexedKeeper) EndBlocker(ctx sdk.Context) bool {
 	hasNewLinks := len(i.currentBlockLinks) > 0
+	height := uint64(ctx.BlockHeight())
 	for _, link := range i.currentBlockLinks {
-		i.nextRankOutLinks.Put(link.From(), link.To(), link.Acc())
-		i.nextRankInLinks.Put(link.To(), link.From(), link.Acc())
+		linkHeight, ok := i.currentBlockLinksHeights[link]
+		if !ok {
+			linkHeight = height
+		}
+		i.nextRankOutLinks.Put(link.From(), link.To(), link.Acc(), linkHeight)
+		i.nextRankInLinks.Put(link.To(), link.From(), link.Acc(), linkHeight)
 	}
 	i.currentBlockLinks = make([]types.CompactLink, 0, 1000) // todo: 1000 hardcoded value
+	i.currentBlockLinksHeights = make(map[types.CompactLink]uint64)
 	return hasNewLinks
 }
 
@@ -80,6 +98,28 @@ func (i *IndexedKeeper) PutLink(ctx sdk.Context, link types.CompactLink) {
 	i.Keeper.PutLink(ctx, link)
 }
 
+func (i *IndexedKeeper) PutLinkAtHeight(ctx sdk.Context, link types.CompactLink, height uint64) {
+	if !ctx.IsCheckTx() {
+		i.currentBlockLinks = append(i.currentBlockLinks, link)
+		i.currentBlockLinksHeights[link] = height
+	}
+
+	i.Keeper.PutLinkAtHeight(ctx, link, height)
+}
+
+// Marks start of tx delivery. Links are buffered outside of tx store,
+// so links of failed tx should be discarded with DiscardTxLinks.
+func (i *IndexedKeeper) BeginTx() {
+	i.txBlockLinksStart = len(i.currentBlockLinks)
+	i.txBufferStart = i.Keeper.bufferedLinksSize()
+}
+
+// Discards links put since BeginTx, links count and cids of failed tx are reverted with its store.
+func (i *IndexedKeeper) DiscardTxLinks() {
+	i.currentBlockLinks = i.currentBlockLinks[:i.txBlockLinksStart]
+	i.Keeper.truncateBufferedLinks(i.txBufferStart)
+}
+
 func (i *IndexedKeeper) GetOutLinks() types.Links {
 	return i.currentRankOutLinks
 }
@@ -116,11 +156,31 @@ func (i *IndexedKeeper) IsLinkExist(link types.CompactLink) bool {
 }
 
 //todo: remove duplicated method (BaseLinksKeeper)
+// read links written by WriteLinks, links keep heights they were created at.
+// Legacy links without heights get zero height, so they are aged from chain start.
+// NOTE: heights are kept as is, while links ages are counted by new chain heights,
+// so links created above current height aren't decayed till chain reaches their heights.
 func (i *IndexedKeeper) LoadFromReader(ctx sdk.Context, reader io.Reader) (err error) {
-	linksCountBytes, err := util.ReadExactlyNBytes(reader, LinksCountBytesSize)
+	firstBytes, err := util.ReadExactlyNBytes(reader, LinksCountBytesSize)
 	if err != nil {
 		return
 	}
+
+	withHeights := bytes.Equal(firstBytes, LinksFormatHeader)
+	linksCountBytes := firstBytes
+	if withHeights {
+		versionBytes, err := util.ReadExactlyNBytes(reader, 8)
+		if err != nil {
+			return err
+		}
+		if version := binary.LittleEndian.Uint64(versionBytes); version != LinksFormatVersion {
+			return fmt.Errorf("unsupported links format version %d", version)
+		}
+		linksCountBytes, err = util.ReadExactlyNBytes(reader, LinksCountBytesSize)
+		if err != nil {
+			return err
+		}
+	}
 	linksCount := binary.LittleEndian.Uint64(linksCountBytes)
 
 	for j := uint64(0); j < linksCount; j++ {
@@ -128,7 +188,15 @@ func (i *IndexedKeeper) LoadFromReader(ctx sdk.Context, reader io.Reader) (err e
 		if err != nil {
 			return err
 		}
-		i.PutLink(ctx, types.UnmarshalBinaryLink(linkBytes))
+		height := uint64(0)
+		if withHeights {
+			heightBytes, err := util.ReadExactlyNBytes(reader, LinkHeightBytesSize)
+			if err != nil {
+				return err
+			}
+			height = binary.LittleEndian.Uint64(heightBytes)
+		}
+		i.PutLinkAtHeight(ctx, types.UnmarshalBinaryLink(linkBytes), height)
 	}
 	return
 }
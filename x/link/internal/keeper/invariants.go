@@ -0,0 +1,40 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cybercongress/go-cyber/x/link/exported"
+	"github.com/cybercongress/go-cyber/x/link/internal/types"
+)
+
+func RegisterInvariants(ir sdk.InvariantRegistry, k exported.IndexedKeeperI) {
+	ir.RegisterRoute(types.ModuleName, "links-count",
+		LinksCountInvariant(k))
+}
+
+// Links of current block are buffered and written to store only at commit,
+// while links count is incremented on each delivered link. Links of failed txs
+// are discarded from buffer, as their links count increments are reverted.
+func LinksCountInvariant(k exported.IndexedKeeperI) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		storedLinks := uint64(0)
+		k.IterateBinaryLinks(ctx, func(_ []byte) { storedLinks++ })
+		currentBlockLinks := uint64(len(k.GetCurrentBlockLinks()))
+
+		linksCount := k.GetLinksCount(ctx)
+		if storedLinks+currentBlockLinks != linksCount {
+			msg = fmt.Sprintf(
+				"links count %d doesn't match stored %d and current block %d links",
+				linksCount, storedLinks, currentBlockLinks,
+			)
+			broken = true
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "links count", msg), broken
+	}
+}
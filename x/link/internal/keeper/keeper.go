@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"bytes"
+	"sort"
 	"sync"
 
 	"github.com/cybercongress/go-cyber/store"
@@ -15,11 +16,18 @@ import (
 
 const (
 	LinkBytesSize       = uint64(24)
+	LinkHeightBytesSize = uint64(8)
 	LinksCountBytesSize = uint64(8)
 )
 
 const defaultBufferSize = 65536
 
+// Links exported with heights are preceded by format header and version,
+// legacy links without heights are preceded by links count only.
+var LinksFormatHeader = []byte("cbdlinks")
+
+const LinksFormatVersion = uint64(1)
+
 var DefaultLinkFilter = func(l types.CompactLink) bool { return true }
 
 type Keeper struct {
@@ -27,6 +35,8 @@ type Keeper struct {
 	storeKey    sdk.StoreKey
 	buffer      *bytes.Buffer
 	mu 			*sync.Mutex
+	// links put with heights they were created at (imported at genesis), written at commit too
+	heightBuffers map[uint64]*bytes.Buffer
 }
 
 func NewLinkKeeper(ms store.MainKeeper, storeKey sdk.StoreKey) *Keeper {
@@ -35,9 +45,11 @@ func NewLinkKeeper(ms store.MainKeeper, storeKey sdk.StoreKey) *Keeper {
 		ms:       ms,
 		buffer:   bytes.NewBuffer(make([]byte, 0, defaultBufferSize)),
 		mu:		  new(sync.Mutex),
+		heightBuffers: make(map[uint64]*bytes.Buffer),
 	}
 }
 
+// Links are buffered outside of tx store, links of simulated txs are never committed.
 func (lk Keeper) PutLink(ctx sdk.Context, link types.CompactLink) {
 	lk.mu.Lock()
 	defer lk.mu.Unlock()
@@ -45,7 +57,38 @@ func (lk Keeper) PutLink(ctx sdk.Context, link types.CompactLink) {
 	if uint64(len(linkAsBytes)) != LinkBytesSize {
 		panic("invalid element length")
 	}
-	lk.buffer.Write(linkAsBytes)
+	if !ctx.IsCheckTx() {
+		lk.buffer.Write(linkAsBytes)
+	}
+	lk.ms.IncrementLinksCount(ctx)
+}
+
+func (lk Keeper) bufferedLinksSize() int {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	return lk.buffer.Len()
+}
+
+func (lk Keeper) truncateBufferedLinks(size int) {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	lk.buffer.Truncate(size)
+}
+
+// Puts link created at given height, it's written to store at commit under that height.
+func (lk Keeper) PutLinkAtHeight(ctx sdk.Context, link types.CompactLink, height uint64) {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	linkAsBytes := link.MarshalBinary()
+	if uint64(len(linkAsBytes)) != LinkBytesSize {
+		panic("invalid element length")
+	}
+	buffer, ok := lk.heightBuffers[height]
+	if !ok {
+		buffer = new(bytes.Buffer)
+		lk.heightBuffers[height] = buffer
+	}
+	buffer.Write(linkAsBytes)
 	lk.ms.IncrementLinksCount(ctx)
 }
 
@@ -58,10 +101,10 @@ func (lk Keeper) GetAllLinksFiltered(ctx sdk.Context, filter types.LinkFilter) (
 	inLinks := make(map[types.CidNumber]types.CidLinks)
 	outLinks := make(map[types.CidNumber]types.CidLinks)
 
-	lk.IterateLinks(ctx, func(link types.CompactLink) {
+	lk.IterateLinksWithHeight(ctx, func(link types.CompactLink, height uint64) {
 		if filter(link) {
-			types.Links(outLinks).Put(link.From(), link.To(), link.Acc())
-			types.Links(inLinks).Put(link.To(), link.From(), link.Acc())
+			types.Links(outLinks).Put(link.From(), link.To(), link.Acc(), height)
+			types.Links(inLinks).Put(link.To(), link.From(), link.Acc(), height)
 		}
 	})
 
@@ -79,6 +122,19 @@ func (lk Keeper) IterateLinks(ctx sdk.Context, process func(link types.CompactLi
 }
 
 func (lk Keeper) IterateBinaryLinks(ctx sdk.Context, process func(bytes []byte)) {
+	lk.iterateBinaryLinksWithHeight(ctx, func(bytes []byte, _ uint64) {
+		process(bytes)
+	})
+}
+
+// links are stored by height of block they were committed at
+func (lk Keeper) IterateLinksWithHeight(ctx sdk.Context, process func(link types.CompactLink, height uint64)) {
+	lk.iterateBinaryLinksWithHeight(ctx, func(bytes []byte, height uint64) {
+		process(types.UnmarshalBinaryLink(bytes), height)
+	})
+}
+
+func (lk Keeper) iterateBinaryLinksWithHeight(ctx sdk.Context, process func(bytes []byte, height uint64)) {
 	store := ctx.KVStore(lk.storeKey)
 
 	iterator := store.Iterator(nil, nil)
@@ -88,19 +144,32 @@ func (lk Keeper) IterateBinaryLinks(ctx sdk.Context, process func(bytes []byte))
 		value := iterator.Value()
 		if (len(value) == 0) { continue }
 
+		height := binary.LittleEndian.Uint64(iterator.Key())
 		links := len(value)/int(LinkBytesSize)
 
 		for i := 0 ; i < links; i++ {
 			elementBytes := value[:LinkBytesSize]
 			value = value[LinkBytesSize:]
-			process(elementBytes)
+			process(elementBytes, height)
 		}
 	}
 }
 
-// write links to writer in binary format: <links_count><cid_number_from><cid_number_to><acc_number>...
+// write links to writer in binary format:
+// <header><version><links_count><cid_number_from><cid_number_to><acc_number><height>...
+// where height is height of block link was committed at
 func (lk Keeper) WriteLinks(ctx sdk.Context, writer io.Writer) (err error) {
+	_, err = writer.Write(LinksFormatHeader)
+	if err != nil {
+		return
+	}
 	uintAsBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(uintAsBytes, LinksFormatVersion)
+	_, err = writer.Write(uintAsBytes)
+	if err != nil {
+		return
+	}
+
 	linksCount := lk.GetLinksCount(ctx)
 	binary.LittleEndian.PutUint64(uintAsBytes, linksCount)
 	_, err = writer.Write(uintAsBytes)
@@ -108,14 +177,19 @@ func (lk Keeper) WriteLinks(ctx sdk.Context, writer io.Writer) (err error) {
 		return
 	}
 
-	lk.IterateBinaryLinks(ctx, func(bytes []byte) {
+	lk.iterateBinaryLinksWithHeight(ctx, func(bytes []byte, height uint64) {
+		if err != nil {
+			return
+		}
 		_, err = writer.Write(bytes)
 		if err != nil {
 			return
 		}
+		binary.LittleEndian.PutUint64(uintAsBytes, height)
+		_, err = writer.Write(uintAsBytes)
 	})
 
-	return nil
+	return
 }
 
 func (lk Keeper) Commit(ctx sdk.Context) {
@@ -123,14 +197,32 @@ func (lk Keeper) Commit(ctx sdk.Context) {
 	defer func() {
 		lk.mu.Unlock()
 		lk.buffer.Reset()
+		for height := range lk.heightBuffers {
+			delete(lk.heightBuffers, height)
+		}
 	}()
 
-	if lk.buffer.Len() > 0 {
-		versionAsBytes := make([]byte, 8)
-		binary.LittleEndian.PutUint64(versionAsBytes, uint64(ctx.BlockHeight()))
-		store := ctx.KVStore(lk.storeKey)
+	// imported links heights could match heights of new blocks, so links are appended to stored ones
+	heights := make([]uint64, 0, len(lk.heightBuffers))
+	for height := range lk.heightBuffers {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	for _, height := range heights {
+		lk.appendLinks(ctx, height, lk.heightBuffers[height].Bytes())
+	}
 
-		store.Set(versionAsBytes, lk.buffer.Bytes())
+	if lk.buffer.Len() > 0 {
+		lk.appendLinks(ctx, uint64(ctx.BlockHeight()), lk.buffer.Bytes())
 	}
 
 }
+
+func (lk Keeper) appendLinks(ctx sdk.Context, height uint64, links []byte) {
+	versionAsBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(versionAsBytes, height)
+	store := ctx.KVStore(lk.storeKey)
+
+	stored := store.Get(versionAsBytes)
+	store.Set(versionAsBytes, append(stored[:len(stored):len(stored)], links...))
+}
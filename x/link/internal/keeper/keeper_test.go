@@ -0,0 +1,139 @@
+package keeper
+
+import (
+	"bytes"
+	"testing"
+
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cybercongress/go-cyber/store"
+	"github.com/cybercongress/go-cyber/x/link/internal/types"
+)
+
+func createTestKeeper(t *testing.T) (sdk.Context, *IndexedKeeper) {
+	mainKey := sdk.NewKVStoreKey("main")
+	linksKey := sdk.NewKVStoreKey("links")
+
+	db := dbm.NewMemDB()
+	ms := sdkstore.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(mainKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(linksKey, sdk.StoreTypeIAVL, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{Height: 1}, false, log.NewNopLogger())
+	keeper := NewIndexedKeeper(NewLinkKeeper(store.NewMainKeeper(mainKey), linksKey))
+	keeper.Load(ctx, ctx)
+	return ctx, keeper
+}
+
+func commitBlock(ctx sdk.Context, keeper *IndexedKeeper, height int64) {
+	ctx = ctx.WithBlockHeight(height)
+	keeper.EndBlocker(ctx)
+	keeper.Commit(ctx)
+}
+
+func linksHeights(ctx sdk.Context, keeper *IndexedKeeper) map[types.CompactLink]uint64 {
+	heights := make(map[types.CompactLink]uint64)
+	keeper.IterateLinksWithHeight(ctx, func(link types.CompactLink, height uint64) {
+		heights[link] = height
+	})
+	return heights
+}
+
+func TestLinksGenesisKeepsHeights(t *testing.T) {
+	ctx, keeper := createTestKeeper(t)
+	link1 := types.NewLink(0, 1, 0)
+	link2 := types.NewLink(1, 2, 1)
+	link3 := types.NewLink(2, 0, 0)
+
+	keeper.PutLink(ctx, link1)
+	commitBlock(ctx, keeper, 5)
+	keeper.PutLink(ctx, link2)
+	keeper.PutLink(ctx, link3)
+	commitBlock(ctx, keeper, 7)
+
+	exported := new(bytes.Buffer)
+	require.NoError(t, keeper.WriteLinks(ctx, exported))
+	require.Equal(t, int(16+LinksCountBytesSize+3*(LinkBytesSize+LinkHeightBytesSize)), exported.Len())
+
+	// links imported at genesis keep their heights, even if new chain commits links at the same height
+	newCtx, newKeeper := createTestKeeper(t)
+	require.NoError(t, newKeeper.LoadFromReader(newCtx, bytes.NewReader(exported.Bytes())))
+	link4 := types.NewLink(0, 2, 1)
+	newKeeper.PutLink(newCtx, link4)
+	commitBlock(newCtx, newKeeper, 5)
+
+	expected := map[types.CompactLink]uint64{link1: 5, link2: 7, link3: 7, link4: 5}
+	require.Equal(t, expected, linksHeights(newCtx, newKeeper))
+	require.Equal(t, uint64(4), newKeeper.GetLinksCount(newCtx))
+	for link, height := range expected {
+		require.Equal(t, height, newKeeper.GetNextOutLinks()[link.From()][link.To()][link.Acc()])
+	}
+
+	_, broken := LinksCountInvariant(newKeeper)(newCtx)
+	require.False(t, broken)
+}
+
+func TestLinksGenesisFormats(t *testing.T) {
+	link1 := types.NewLink(0, 1, 0)
+	link2 := types.NewLink(1, 2, 1)
+
+	// legacy links are written without header and heights
+	legacy := []byte{2, 0, 0, 0, 0, 0, 0, 0}
+	legacy = append(legacy, link1.MarshalBinary()...)
+	legacy = append(legacy, link2.MarshalBinary()...)
+
+	ctx, keeper := createTestKeeper(t)
+	require.NoError(t, keeper.LoadFromReader(ctx, bytes.NewReader(legacy)))
+	commitBlock(ctx, keeper, 1)
+	require.Equal(t, map[types.CompactLink]uint64{link1: 0, link2: 0}, linksHeights(ctx, keeper))
+
+	// truncated files and unknown versions are rejected
+	exported := new(bytes.Buffer)
+	require.NoError(t, keeper.WriteLinks(ctx, exported))
+	require.Equal(t, LinksFormatHeader, exported.Bytes()[:8])
+
+	ctx, keeper = createTestKeeper(t)
+	require.Error(t, keeper.LoadFromReader(ctx, bytes.NewReader(exported.Bytes()[:exported.Len()-1])))
+	unknownVersion := append([]byte{}, exported.Bytes()...)
+	unknownVersion[8] = 2
+	ctx, keeper = createTestKeeper(t)
+	require.Error(t, keeper.LoadFromReader(ctx, bytes.NewReader(unknownVersion)))
+}
+
+func TestLinksOfFailedTxAreDiscarded(t *testing.T) {
+	ctx, keeper := createTestKeeper(t)
+	link1 := types.NewLink(0, 1, 0)
+	link2 := types.NewLink(1, 2, 1)
+	link3 := types.NewLink(2, 0, 0)
+
+	// failed tx store writes are not committed, so its links count increments are reverted
+	keeper.BeginTx()
+	failedCtx, _ := ctx.CacheContext()
+	keeper.PutLink(failedCtx, link1)
+	keeper.PutLink(failedCtx, link2)
+	keeper.DiscardTxLinks()
+
+	keeper.BeginTx()
+	deliveredCtx, write := ctx.CacheContext()
+	keeper.PutLink(deliveredCtx, link3)
+	write()
+
+	// simulated txs are run on check state, which is never committed
+	checkCtx, _ := ctx.CacheContext()
+	keeper.PutLink(checkCtx.WithIsCheckTx(true), link1)
+
+	_, broken := LinksCountInvariant(keeper)(ctx)
+	require.False(t, broken)
+	commitBlock(ctx, keeper, 2)
+
+	require.Equal(t, map[types.CompactLink]uint64{link3: 2}, linksHeights(ctx, keeper))
+	require.Equal(t, uint64(1), keeper.GetLinksCount(ctx))
+	_, broken = LinksCountInvariant(keeper)(ctx)
+	require.False(t, broken)
+}
@@ -5,23 +5,23 @@ import (
 )
 
 // map of map, where first key is cid, second key is account.String()
-// second map is used as set for fast contains check
+// second map values are link creation heights (block at which link was committed)
 type Links map[CidNumber]CidLinks
-type CidLinks map[CidNumber]map[AccNumber]struct{}
+type CidLinks map[CidNumber]map[AccNumber]uint64
 
 type Cid string // 32 byte string
 type CidNumber uint64
 
-func (links Links) Put(from CidNumber, to CidNumber, acc AccNumber) {
+func (links Links) Put(from CidNumber, to CidNumber, acc AccNumber, height uint64) {
 	cidLinks := links[from]
 	if cidLinks == nil {
 		cidLinks = make(CidLinks)
 	}
 	users := cidLinks[to]
 	if users == nil {
-		users = make(map[AccNumber]struct{})
+		users = make(map[AccNumber]uint64)
 	}
-	users[acc] = struct{}{}
+	users[acc] = height
 	cidLinks[to] = users
 	links[from] = cidLinks
 }
@@ -29,8 +29,8 @@ func (links Links) Put(from CidNumber, to CidNumber, acc AccNumber) {
 func (links Links) PutAll(newLinks Links) {
 	for from := range newLinks {
 		for to := range newLinks[from] {
-			for u := range newLinks[from][to] {
-				links.Put(from, to, u)
+			for u, height := range newLinks[from][to] {
+				links.Put(from, to, u, height)
 			}
 		}
 	}
@@ -43,9 +43,9 @@ func (links Links) Copy() Links {
 	for from := range links {
 		fromLinks := make(CidLinks, len(links[from]))
 		for to := range links[from] {
-			users := make(map[AccNumber]struct{}, len(links[from][to]))
-			for u := range links[from][to] {
-				users[u] = struct{}{}
+			users := make(map[AccNumber]uint64, len(links[from][to]))
+			for u, height := range links[from][to] {
+				users[u] = height
 			}
 			fromLinks[to] = users
 		}
@@ -6,4 +6,9 @@ const (
 	StoreKey = ModuleName
 
 	RouterKey = ModuleName
+
+	// store of cid to cid number index
+	CidStoreKey = "cid_index"
+	// store of cid number to cid index
+	CidReverseStoreKey = "cid_index_reverse"
 )
@@ -68,7 +68,9 @@ func (AppModule) Name() string {
 
 func (am AppModule) InitGenesis(_ sdk.Context, _ json.RawMessage) []types.ValidatorUpdate { return nil }
 
-func (am AppModule) RegisterInvariants(_ sdk.InvariantRegistry) {}
+func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+	RegisterInvariants(ir, am.indexedKeeper)
+}
 
 func (am AppModule) Route() string { return RouterKey }
 
@@ -15,8 +15,12 @@ const (
 	QueryCalculationWindow = types.QueryCalculationWindow
 	QueryDampingFactor     = types.QueryDampingFactor
 	QueryTolerance         = types.QueryTolerance
+	TreeDomainSeparationUpgrade = types.TreeDomainSeparationUpgrade
 	CPU        			   = types.CPU
 	GPU        			   = types.GPU
+	WithoutProofs          = types.WithoutProofs
+	WithIndexProofs        = types.WithIndexProofs
+	WithMultiProof         = types.WithMultiProof
 )
 
 var (
@@ -29,6 +33,13 @@ var (
 	ParamKeyTable  		= types.ParamKeyTable
 	NewParams	        = types.NewParams
 	DefaultParams       = types.DefaultParams
+	NewPersonalizedRankConfig = types.NewPersonalizedRankConfig
+	NewCalcContext      = types.NewCalcContext
+	CalculateRank       = keeper.CalculateRank
+	SimulateRank        = keeper.SimulateRank
+	BuildTopK           = types.BuildTopK
+
+	ErrCidNotRanked     = types.ErrCidNotRanked
 
 	ModuleCdc           = types.ModuleCdc
 )
@@ -39,4 +50,8 @@ type (
 	GenesisState = types.GenesisState
 	Params       = types.Params
 	ComputeUnit  = types.ComputeUnit
+	PersonalizedRankConfig = types.PersonalizedRankConfig
+	Rank                   = types.Rank
+	CalculationContext     = types.CalculationContext
+	RankSnapshot           = types.RankSnapshot
 )
@@ -1,7 +1,12 @@
 package cli
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/cybercongress/go-cyber/x/rank/internal/types"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -10,6 +15,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
+	rpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+
+	"github.com/cybercongress/go-cyber/merkle"
 )
 
 // GetQueryCmd returns the cli query commands for the minting module.
@@ -28,6 +36,7 @@ func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 			GetCmdQueryCalculationWindow(cdc),
 			GetCmdQueryDampingFactor(cdc),
 			GetCmdQueryTolerance(cdc),
+			GetCmdVerifyRank(cdc),
 		)...,
 	)
 
@@ -137,3 +146,81 @@ func GetCmdQueryTolerance(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// rank rpc result of cyberd node
+type rankWithProofs struct {
+	Proofs      []merkle.Proof `json:"proofs"`
+	Rank        float64        `amino:"unsafe" json:"rank"`
+	Height      int64          `json:"height"`
+	TreeVersion merkle.Version `json:"treeVersion"`
+	CidNumber   uint64         `json:"cidNumber"`
+	LeavesCount int            `json:"leavesCount"`
+}
+
+type VerifiedRank struct {
+	Cid       string  `json:"cid"`
+	CidNumber uint64  `json:"cid_number"`
+	Rank      float64 `amino:"unsafe" json:"rank"`
+	Height    int64   `json:"height"`
+	RootHash  string  `json:"root_hash"`
+	Proofs    string  `json:"proofs"` // hex of proofs wire encoding
+}
+
+// GetCmdVerifyRank implements a command to query rank of cid with proofs from
+// untrusted node and verify it against app hash of trusted block header.
+func GetCmdVerifyRank(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <cid>",
+		Short: "Query rank of cid and verify it against app hash of trusted block header",
+		Long: `verify queries rank of cid with merkle proofs and checks them against app hash of block header
+verified by light client, which is root hash of rank merkle tree.
+Cid number, which is leaf index of cid in the tree, and tree leaves count are taken from queried node,
+as they aren't committed by app hash. So verified is rank of cid number, not binding of cid to it.
+Requires --trust-node=false and --chain-id.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			if cliCtx.TrustNode {
+				return errors.New("rank verification requires --trust-node=false")
+			}
+			cid := args[0]
+
+			client, err := rpcclient.New(cliCtx.NodeURI)
+			if err != nil {
+				return err
+			}
+			var result rankWithProofs
+			_, err = client.Call("rank", map[string]interface{}{"cid": cid, "proof": true}, &result)
+			if err != nil {
+				return err
+			}
+			if !result.TreeVersion.IsValid() {
+				return fmt.Errorf("unknown rank tree version %d", result.TreeVersion)
+			}
+
+			// app hash of block is in header of next block
+			header, err := cliCtx.Verify(result.Height + 1)
+			if err != nil {
+				return err
+			}
+			rootHash := header.AppHash
+
+			rankBytes := make([]byte, 8)
+			binary.LittleEndian.PutUint64(rankBytes, math.Float64bits(result.Rank))
+			if !merkle.VerifyProof(
+				sha256.New(), result.TreeVersion, rootHash, int(result.CidNumber), result.LeavesCount, rankBytes, result.Proofs,
+			) {
+				return fmt.Errorf("rank %v of cid %s doesn't match rank tree at height %d", result.Rank, cid, result.Height)
+			}
+
+			return cliCtx.PrintOutput(VerifiedRank{
+				Cid:       cid,
+				CidNumber: result.CidNumber,
+				Rank:      result.Rank,
+				Height:    result.Height,
+				RootHash:  hex.EncodeToString(rootHash),
+				Proofs:    hex.EncodeToString(merkle.EncodeProofs(result.Proofs)),
+			})
+		},
+	}
+}
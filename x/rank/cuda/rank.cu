@@ -82,9 +82,8 @@ double find_max_ranks_diff(double *prevRank, double *newRank, uint64_t rankSize)
 __global__
 void calculateCidTotalOutStake(
     uint64_t cidsSize,
-    uint64_t *stakes,                                        /*array index - user index*/
     uint64_t *outLinksStartIndex, uint32_t *outLinksCount,   /*array index - cid index*/
-    uint64_t *outLinksUsers,                                 /*all out links from all users*/
+    uint64_t *outLinksStakes,                                /*all out links stakes from all users*/
     /*returns*/ uint64_t *cidsTotalOutStakes                 /*array index - cid index*/
 ) {
 
@@ -94,7 +93,7 @@ void calculateCidTotalOutStake(
     for (uint64_t i = index; i < cidsSize; i += stride) {
         uint64_t totalOutStake = 0;
         for (uint64_t j = outLinksStartIndex[i]; j < outLinksStartIndex[i] + outLinksCount[i]; j++) {
-           totalOutStake += stakes[outLinksUsers[j]];
+           totalOutStake += outLinksStakes[j];
         }
         cidsTotalOutStakes[i] = totalOutStake;
     }
@@ -147,8 +146,7 @@ __global__
 void getCompressedInLinks(
     uint64_t cidsSize,
     uint64_t *inLinksStartIndex, uint32_t *inLinksCount, uint64_t *cidsTotalOutStakes,   /*array index - cid index*/
-    uint64_t *inLinksOuts, uint64_t *inLinksUsers,                                       /*all incoming links from all users*/
-    uint64_t *stakes,                                                                    /*array index - user index*/
+    uint64_t *inLinksOuts, uint64_t *inLinksStakes,                                      /*all incoming links from all users*/
     uint64_t *compressedInLinksStartIndex, uint32_t *compressedInLinksCount,             /*array index - cid index*/
     /*returns*/ CompressedInLink *compressedInLinks                                      /*all incoming compressed links*/
 ) {
@@ -166,7 +164,7 @@ void getCompressedInLinks(
 
         if(inLinksCount[i] == 1) {
             uint64_t oppositeCid = inLinksOuts[inLinksStartIndex[i]];
-            uint64_t compressedLinkStake = stakes[inLinksUsers[inLinksStartIndex[i]]];
+            uint64_t compressedLinkStake = inLinksStakes[inLinksStartIndex[i]];
             double weight = ddiv_rn(&compressedLinkStake, &cidsTotalOutStakes[oppositeCid]);
             if (isnan(weight)) { weight = 0; }
             compressedInLinks[compressedLinksIndex] = CompressedInLink {oppositeCid, weight};
@@ -177,7 +175,7 @@ void getCompressedInLinks(
         uint64_t lastLinkIndex = inLinksStartIndex[i] + inLinksCount[i] - 1;
         for(uint64_t j = inLinksStartIndex[i]; j < lastLinkIndex + 1; j++) {
 
-            compressedLinkStake += stakes[inLinksUsers[j]];
+            compressedLinkStake += inLinksStakes[j];
             if(j == lastLinkIndex || inLinksOuts[j] != inLinksOuts[j+1]) {
                 uint64_t oppositeCid = inLinksOuts[j];
                 double weight = ddiv_rn(&compressedLinkStake, &cidsTotalOutStakes[oppositeCid]);
@@ -220,11 +218,10 @@ void swap(double* &a, double* &b){
 extern "C" {
 
     void calculate_rank(
-        uint64_t *stakes, uint64_t stakesSize,                    /* User stakes and corresponding array size */
         uint64_t cidsSize, uint64_t linksSize,                    /* Cids count */
         uint32_t *inLinksCount, uint32_t *outLinksCount,          /* array index - cid index*/
-        uint64_t *inLinksOuts, uint64_t *inLinksUsers,            /*all incoming links from all users*/
-        uint64_t *outLinksUsers,                                  /*all outgoing links from all users*/
+        uint64_t *inLinksOuts, uint64_t *inLinksStakes,           /*all incoming links from all users, stakes with decay applied*/
+        uint64_t *outLinksStakes,                                 /*all outgoing links stakes from all users*/
         double *rank,                                             /* array index - cid index*/
         double dampingFactor,                                     /* value of damping factor*/
         double tolerance                                          /* value of needed tolerance */
@@ -247,29 +244,26 @@ extern "C" {
         /*-------------------------------------------------------------------*/
         uint64_t *d_outLinksStartIndex;
         uint32_t *d_outLinksCount;
-        uint64_t *d_outLinksUsers;
-        uint64_t *d_stakes;  // will be used to calculated links weights, should be freed before rank iterations
+        uint64_t *d_outLinksStakes;
         uint64_t *d_cidsTotalOutStakes; // will be used to calculated links weights, should be freed before rank iterations
 
         cudaMalloc(&d_outLinksStartIndex, cidsSize*sizeof(uint64_t));
         cudaMalloc(&d_outLinksCount,      cidsSize*sizeof(uint32_t));
-        cudaMalloc(&d_outLinksUsers,     linksSize*sizeof(uint64_t));
-        cudaMalloc(&d_stakes,           stakesSize*sizeof(uint64_t));
+        cudaMalloc(&d_outLinksStakes,    linksSize*sizeof(uint64_t));
         cudaMalloc(&d_cidsTotalOutStakes, cidsSize*sizeof(uint64_t));   //calculated
 
         cudaMemcpy(d_outLinksStartIndex, outLinksStartIndex, cidsSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
         cudaMemcpy(d_outLinksCount,      outLinksCount,      cidsSize*sizeof(uint32_t), cudaMemcpyHostToDevice);
-        cudaMemcpy(d_outLinksUsers,      outLinksUsers,     linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
-        cudaMemcpy(d_stakes,             stakes,           stakesSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
+        cudaMemcpy(d_outLinksStakes,     outLinksStakes,    linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
 
         calculateCidTotalOutStake<<<CUDA_BLOCKS_NUMBER,CUDA_THREAD_BLOCK_SIZE>>>(
-            cidsSize, d_stakes, d_outLinksStartIndex,
-            d_outLinksCount, d_outLinksUsers, d_cidsTotalOutStakes
+            cidsSize, d_outLinksStartIndex,
+            d_outLinksCount, d_outLinksStakes, d_cidsTotalOutStakes
         );
 
         cudaFree(d_outLinksStartIndex);
         cudaFree(d_outLinksCount);
-        cudaFree(d_outLinksUsers);
+        cudaFree(d_outLinksStakes);
         /*-------------------------------------------------------------------*/
 
 
@@ -319,26 +313,25 @@ extern "C" {
 
         // STEP4: Calculate compressed in links
         /*-------------------------------------------------------------------*/
-        uint64_t *d_inLinksUsers;
+        uint64_t *d_inLinksStakes;
         CompressedInLink *d_compressedInLinks; //calculated
 
-        cudaMalloc(&d_inLinksUsers,                   linksSize*sizeof(uint64_t));
+        cudaMalloc(&d_inLinksStakes,                  linksSize*sizeof(uint64_t));
         cudaMalloc(&d_compressedInLinks,  compressedInLinksSize*sizeof(CompressedInLink));
-        cudaMemcpy(d_inLinksUsers, inLinksUsers,      linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
+        cudaMemcpy(d_inLinksStakes, inLinksStakes,    linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
 
         getCompressedInLinks<<<CUDA_BLOCKS_NUMBER,CUDA_THREAD_BLOCK_SIZE>>>(
             cidsSize,
             d_inLinksStartIndex, d_inLinksCount, d_cidsTotalOutStakes,
-            d_inLinksOuts, d_inLinksUsers, d_stakes,
+            d_inLinksOuts, d_inLinksStakes,
             d_compressedInLinksStartIndex, d_compressedInLinksCount,
             d_compressedInLinks
         );
 
-        cudaFree(d_inLinksUsers);
+        cudaFree(d_inLinksStakes);
         cudaFree(d_inLinksStartIndex);
         cudaFree(d_inLinksCount);
         cudaFree(d_inLinksOuts);
-        cudaFree(d_stakes);
         cudaFree(d_cidsTotalOutStakes);
         /*-------------------------------------------------------------------*/
 
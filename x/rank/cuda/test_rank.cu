@@ -70,35 +70,30 @@ void test_calculateCidTotalOutStake() {
 
     int cidsSize = 6;
     int linksSize = 9;
-    int usersSize = 3;
 
     uint32_t outLinksCount [6] = { 0, 2, 0, 1, 3, 3 };
     uint64_t outLinksStartIndex [6] = { 0, 0, 2, 2, 3, 6 };
-    uint64_t outLinksUsers [9] = { 1, 0, 2, 0, 2, 1, 2, 1, 0};
-    uint64_t stakes [3] = { 1, 2, 3};
+    uint64_t outLinksStakes [9] = { 2, 1, 3, 1, 3, 2, 3, 2, 1};
 
     uint32_t *dev_outLinksCount;
     uint64_t *dev_outLinksStartIndex;
-    uint64_t *dev_outLinksUsers;
-    uint64_t *dev_stakes;
+    uint64_t *dev_outLinksStakes;
     uint64_t *dev_cidsTotalOutStakes;
 
     cudaMalloc(&dev_outLinksCount, cidsSize*sizeof(uint32_t));
     cudaMalloc(&dev_outLinksStartIndex, cidsSize*sizeof(uint64_t));
-    cudaMalloc(&dev_outLinksUsers, linksSize*sizeof(uint64_t));
-    cudaMalloc(&dev_stakes, usersSize*sizeof(uint64_t));
+    cudaMalloc(&dev_outLinksStakes, linksSize*sizeof(uint64_t));
     cudaMalloc(&dev_cidsTotalOutStakes, cidsSize*sizeof(uint64_t));
 
     cudaMemcpy(dev_outLinksCount, outLinksCount, cidsSize*sizeof(uint32_t), cudaMemcpyHostToDevice);
     cudaMemcpy(dev_outLinksStartIndex, outLinksStartIndex, cidsSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
-    cudaMemcpy(dev_outLinksUsers, outLinksUsers, linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
-    cudaMemcpy(dev_stakes, stakes, usersSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
+    cudaMemcpy(dev_outLinksStakes, outLinksStakes, linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
 
     cudaDeviceSynchronize();
     calculateCidTotalOutStake<<<2,3>>>(
-        cidsSize, dev_stakes,
+        cidsSize,
         dev_outLinksStartIndex, dev_outLinksCount,
-        dev_outLinksUsers, dev_cidsTotalOutStakes
+        dev_outLinksStakes, dev_cidsTotalOutStakes
     );
     cudaDeviceSynchronize();
 
@@ -141,7 +136,6 @@ void test_getCompressedInLinks() {
     int cidsSize = 8;
     int linksSize = 11;
     int compressedLinksSize = 8;
-    int usersSize = 3;
 
     uint32_t inLinksCount [8] =           {0,0,1,5,4,0,1,0};
     uint32_t compressedInLinksCount [8] = {0,0,1,3,3,0,1,0};
@@ -149,15 +143,13 @@ void test_getCompressedInLinks() {
     uint64_t compressedInLinksStartIndex [8] =      {0,0,0,1,4,7,7,8};
     uint64_t cidsTotalOutStakes [8] =    {3,3,3,1,6,1,0,3};
     uint64_t inLinksOuts [11]  = {7,1,4,4,4,2,5,0,0,1,3};
-    uint64_t inLinksUsers [11] = {0,2,0,1,2,0,1,1,2,1,1};
-    uint64_t stakes [3] = {3,1,2};
+    uint64_t inLinksStakes [11] = {3,2,3,1,2,3,1,1,2,1,1};
 
     uint64_t *dev_inLinksStartIndex;
     uint32_t *dev_inLinksCount;
     uint64_t *dev_cidsTotalOutStakes;
     uint64_t *dev_inLinksOuts;
-    uint64_t *dev_inLinksUsers;
-    uint64_t *dev_stakes;
+    uint64_t *dev_inLinksStakes;
     uint64_t *dev_compressedInLinksStartIndex;
     uint32_t *dev_compressedInLinksCount;
     CompressedInLink *dev_compressedInLinks;
@@ -166,8 +158,7 @@ void test_getCompressedInLinks() {
     cudaMalloc(&dev_inLinksCount, cidsSize*sizeof(uint32_t));
     cudaMalloc(&dev_cidsTotalOutStakes, cidsSize*sizeof(uint64_t));
     cudaMalloc(&dev_inLinksOuts, linksSize*sizeof(uint64_t));
-    cudaMalloc(&dev_inLinksUsers, linksSize*sizeof(uint64_t));
-    cudaMalloc(&dev_stakes, usersSize*sizeof(uint64_t));
+    cudaMalloc(&dev_inLinksStakes, linksSize*sizeof(uint64_t));
     cudaMalloc(&dev_compressedInLinksStartIndex, cidsSize*sizeof(uint64_t));
     cudaMalloc(&dev_compressedInLinksCount, cidsSize*sizeof(uint32_t));
     cudaMalloc(&dev_compressedInLinks, compressedLinksSize*sizeof(CompressedInLink));
@@ -176,8 +167,7 @@ void test_getCompressedInLinks() {
     cudaMemcpy(dev_inLinksCount, inLinksCount, cidsSize*sizeof(uint32_t), cudaMemcpyHostToDevice);
     cudaMemcpy(dev_cidsTotalOutStakes, cidsTotalOutStakes, cidsSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
     cudaMemcpy(dev_inLinksOuts, inLinksOuts, linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
-    cudaMemcpy(dev_inLinksUsers, inLinksUsers, linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
-    cudaMemcpy(dev_stakes, stakes, usersSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
+    cudaMemcpy(dev_inLinksStakes, inLinksStakes, linksSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
     cudaMemcpy(dev_compressedInLinksStartIndex, compressedInLinksStartIndex, cidsSize*sizeof(uint64_t), cudaMemcpyHostToDevice);
     cudaMemcpy(dev_compressedInLinksCount, compressedInLinksCount, cidsSize*sizeof(uint32_t), cudaMemcpyHostToDevice);
 
@@ -185,8 +175,7 @@ void test_getCompressedInLinks() {
     getCompressedInLinks<<<4,2>>>(
         cidsSize,
         dev_inLinksStartIndex, dev_inLinksCount, dev_cidsTotalOutStakes,
-        dev_inLinksOuts, dev_inLinksUsers,
-        dev_stakes,
+        dev_inLinksOuts, dev_inLinksStakes,
         dev_compressedInLinksStartIndex, compressedInLinksCount,
         dev_compressedInLinks
     );
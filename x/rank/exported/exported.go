@@ -5,6 +5,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/cybercongress/go-cyber/merkle"
+	cbd "github.com/cybercongress/go-cyber/types"
 	"github.com/cybercongress/go-cyber/x/link"
 	"github.com/cybercongress/go-cyber/x/rank/internal/types"
 )
@@ -17,14 +18,20 @@ type StateKeeper interface {
 	BuildSearchIndex(log.Logger) types.SearchIndex
 
 	EndBlocker(sdk.Context, log.Logger)
+	SetTreeVersion(sdk.Context, merkle.Version)
 
 	Search(cidNumber link.CidNumber, page, perPage int) ([]types.RankedCidNumber, int, error)
 	Top(page, perPage int) ([]types.RankedCidNumber, int, error)
+	PersonalizedRank(ctx sdk.Context, cids []link.CidNumber, neurons []cbd.AccNumber, topK int) ([]types.RankedCidNumber, int, error)
 
 	GetRankValue(link.CidNumber) float64
 	GetNetworkRankHash() []byte
 
 	GetLastCidNum() link.CidNumber
-	GetMerkleTree() *merkle.Tree
+	GetNetworkCidCount() uint64
+	GetNetworkRankValues() []float64
+	GetStoredNetworkRankHash(sdk.Context) []byte
+	GetCidsCountBeforeBlock(sdk.Context) uint64
+	GetRankSnapshot([]link.CidNumber, types.SnapshotProofs) (types.RankSnapshot, error)
 	GetIndexError() error
 }
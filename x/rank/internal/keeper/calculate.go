@@ -13,9 +13,12 @@ func CalculateRank(ctx *types.CalculationContext, unit types.ComputeUnit, logger
 	start := time.Now()
 	if unit == types.CPU {
 		//used only for development
-		rank = types.NewRank(calculateRankCPU(ctx), logger, ctx.FullTree)
+		values, _ := calculateRankCPU(ctx)
+		rank = types.NewRankReusingTree(values, ctx.PreviousRank, logger, ctx.FullTree, ctx.TreeVersion)
 	} else {
-		rank = types.NewRank(calculateRankGPU(ctx, logger), logger, ctx.FullTree)
+		rank = types.NewRankReusingTree(
+			calculateRankGPU(ctx, logger), ctx.PreviousRank, logger, ctx.FullTree, ctx.TreeVersion,
+		)
 	}
 	logger.Info(
 		"Rank calculated", "time", time.Since(start), "links", ctx.LinksCount, "objects", ctx.CidsCount,
@@ -24,8 +27,21 @@ func CalculateRank(ctx *types.CalculationContext, unit types.ComputeUnit, logger
 	return
 }
 
+// Calculates rank on CPU and returns performed iterations as well. Used by offline tools.
+func SimulateRank(ctx *types.CalculationContext, logger log.Logger) (types.Rank, int) {
+	start := time.Now()
+	values, steps := calculateRankCPU(ctx)
+	rank := types.NewRankReusingTree(values, ctx.PreviousRank, logger, ctx.FullTree, ctx.TreeVersion)
+	logger.Info(
+		"Rank simulated", "time", time.Since(start), "iterations", steps, "links", ctx.LinksCount,
+		"objects", ctx.CidsCount, "hash", hex.EncodeToString(rank.MerkleTree.RootHash()),
+	)
+	return rank, steps
+}
+
 func CalculateRankInParallel(
-	ctx *types.CalculationContext, rankChan chan types.Rank, err chan error, unit types.ComputeUnit, logger log.Logger,
+	ctx *types.CalculationContext, rankChan chan types.Rank, err chan error, unit types.ComputeUnit,
+	proofTrees *types.ProofTreeStore, logger log.Logger,
 ) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -34,5 +50,15 @@ func CalculateRankInParallel(
 	}()
 
 	rank := CalculateRank(ctx, unit, logger)
+
+	// files are written before rank is handled, so they are ready when rank is applied
+	if proofTrees != nil {
+		start := time.Now()
+		if writeErr := proofTrees.WriteNext(rank); writeErr != nil {
+			logger.Error("Error during rank proofs writing " + writeErr.Error())
+		} else {
+			logger.Info("Rank proofs written", "time", time.Since(start))
+		}
+	}
 	rankChan <- rank
 }
@@ -8,7 +8,8 @@ import (
 )
 
 
-func calculateRankCPU(ctx *types.CalculationContext) []float64 {
+// Returns rank values and performed iterations.
+func calculateRankCPU(ctx *types.CalculationContext) ([]float64, int) {
 
 	inLinks := ctx.GetInLinks()
 	tolerance := ctx.GetTolerance()
@@ -16,7 +17,7 @@ func calculateRankCPU(ctx *types.CalculationContext) []float64 {
 
 	size := ctx.GetCidsCount()
 	if size == 0 {
-		return []float64{}
+		return []float64{}, 0
 	}
 
 	rank := make([]float64, size)
@@ -45,7 +46,7 @@ func calculateRankCPU(ctx *types.CalculationContext) []float64 {
 		steps++
 	}
 
-	return rank
+	return rank, steps
 }
 
 func step(ctx *types.CalculationContext, defaultRankWithCorrection float64, dampingFactor float64, prevrank []float64) []float64 {
@@ -77,8 +78,8 @@ func getOverallLinkStake(ctx *types.CalculationContext, from link.CidNumber, to
 
 	stake := uint64(0)
 	users := ctx.GetOutLinks()[from][to]
-	for user := range users {
-		stake += ctx.GetStakes()[user]
+	for user, height := range users {
+		stake += ctx.GetLinkStake(user, height)
 	}
 	return stake
 }
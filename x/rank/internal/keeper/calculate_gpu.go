@@ -30,32 +30,24 @@ func calculateRankGPU(ctx *types.CalculationContext, logger log.Logger) []float6
 	outLinks := ctx.GetOutLinks()
 
 	cidsCount := ctx.GetCidsCount()
-	//linksCount := uint64(0)
-	stakesCount := len(ctx.GetStakes())
 
 	rank := make([]float64, cidsCount)
 	inLinksCount := make([]uint32, cidsCount)
 	outLinksCount := make([]uint32, cidsCount)
 
+	// stakes are passed per link, so links weights decay is applied once here
 	inLinksOuts := make([]uint64, 0)
-	inLinksUsers := make([]uint64, 0)
-	outLinksUsers := make([]uint64, 0)
-
-	// TODO reduce size of stake by passing only participating in linking stakes.
-	// TODO need to investigate why GetStakes returns accounts with missed index, some sdk module?
-	stakes := make([]uint64, stakesCount+10)
-	for acc, stake := range ctx.GetStakes() {
-		stakes[uint64(acc)] = stake
-	}
+	inLinksStakes := make([]uint64, 0)
+	outLinksStakes := make([]uint64, 0)
 
 	for i := int64(0); i < cidsCount; i++ {
 
 		if inLinks, sortedCids, ok := ctx.GetSortedInLinks(link.CidNumber(i)); ok {
 			for _, cid := range sortedCids {
 				inLinksCount[i]  += uint32(len(inLinks[cid]))
-				for acc := range inLinks[cid] {
+				for acc, height := range inLinks[cid] {
 					inLinksOuts = append(inLinksOuts, uint64(cid))
-					inLinksUsers = append(inLinksUsers, uint64(acc))
+					inLinksStakes = append(inLinksStakes, ctx.GetLinkStake(acc, height))
 				}
 			}
 		}
@@ -63,17 +55,14 @@ func calculateRankGPU(ctx *types.CalculationContext, logger log.Logger) []float6
 		if outLinks, ok := outLinks[link.CidNumber(i)]; ok {
 			for _, accs := range outLinks {
 				outLinksCount[i]  += uint32(len(accs))
-				for acc := range accs {
-					outLinksUsers = append(outLinksUsers, uint64(acc))
+				for acc, height := range accs {
+					outLinksStakes = append(outLinksStakes, ctx.GetLinkStake(acc, height))
 				}
 			}
 		}
 	}
 
 	/* Convert to C types */
-	cStakes := (*C.ulong)(&stakes[0])
-
-	cStakesSize := C.ulong(len(stakes))
 	cCidsSize := C.ulong(len(inLinksCount))
 	cLinksSize := C.ulong(len(inLinksOuts))
 
@@ -81,8 +70,8 @@ func calculateRankGPU(ctx *types.CalculationContext, logger log.Logger) []float6
 	cOutLinksCount := (*C.uint)(&outLinksCount[0])
 
 	cInLinksOuts := (*C.ulong)(&inLinksOuts[0])
-	cInLinksUsers := (*C.ulong)(&inLinksUsers[0])
-	cOutLinksUsers := (*C.ulong)(&outLinksUsers[0])
+	cInLinksStakes := (*C.ulong)(&inLinksStakes[0])
+	cOutLinksStakes := (*C.ulong)(&outLinksStakes[0])
 
 	cDampingFactor := C.double(dampingFactor)
 	cTolerance := C.double(tolerance)
@@ -92,9 +81,9 @@ func calculateRankGPU(ctx *types.CalculationContext, logger log.Logger) []float6
 	start = time.Now()
 	cRank := (*C.double)(&rank[0])
 	C.calculate_rank(
-		cStakes, cStakesSize, cCidsSize, cLinksSize,
+		cCidsSize, cLinksSize,
 		cInLinksCount, cOutLinksCount,
-		cInLinksOuts, cInLinksUsers, cOutLinksUsers,
+		cInLinksOuts, cInLinksStakes, cOutLinksStakes,
 		cRank, cDampingFactor, cTolerance,
 	)
 	logger.Info("Rank: gpu calculations", "time", time.Since(start))
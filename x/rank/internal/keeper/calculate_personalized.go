@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	"github.com/cybercongress/go-cyber/x/link"
+	"github.com/cybercongress/go-cyber/x/rank/internal/types"
+)
+
+// Personalized (topic-sensitive) PageRank.
+// Teleport probability (1-d) is spread over seed cids only instead of all cids,
+// rank mass of cids without outgoing stake is returned to seeds as well.
+// NOTE: off-consensus, on-demand calculation. Returns rank values and performed iterations.
+func calculatePersonalizedRankCPU(ctx *types.CalculationContext, seeds []link.CidNumber, maxIterations int) ([]float64, int) {
+
+	size := ctx.GetCidsCount()
+	if size == 0 || len(seeds) == 0 {
+		return []float64{}, 0
+	}
+
+	tolerance := ctx.GetTolerance()
+	dampingFactor := ctx.GetDampingFactor()
+
+	seedShare := 1.0 / float64(len(seeds))
+	rank := make([]float64, size)
+	for _, seed := range seeds {
+		rank[seed] = seedShare
+	}
+
+	outStakes := make(map[link.CidNumber]uint64, len(ctx.GetOutLinks()))
+	for from := range ctx.GetOutLinks() {
+		outStakes[from] = getOverallOutLinksStake(ctx, from)
+	}
+
+	steps := 0
+	change := tolerance + 1
+	for change > tolerance && steps < maxIterations {
+		nextRank := personalizedStep(ctx, dampingFactor, seeds, seedShare, outStakes, rank)
+		change = calculateChange(rank, nextRank)
+		rank = nextRank
+		steps++
+	}
+
+	return rank, steps
+}
+
+func personalizedStep(
+	ctx *types.CalculationContext, dampingFactor float64, seeds []link.CidNumber, seedShare float64,
+	outStakes map[link.CidNumber]uint64, prevrank []float64,
+) []float64 {
+
+	rank := make([]float64, len(prevrank))
+
+	danglingRank := float64(0)
+	for i, r := range prevrank {
+		if outStakes[link.CidNumber(i)] == 0 {
+			danglingRank += r
+		}
+	}
+
+	for cid := range ctx.GetInLinks() {
+		_, sortedCids, ok := ctx.GetSortedInLinks(cid)
+		if !ok {
+			continue
+		}
+
+		ksum := float64(0)
+		for _, j := range sortedCids {
+			jCidOutStake := outStakes[j]
+			if jCidOutStake == 0 {
+				continue
+			}
+			weight := float64(getOverallLinkStake(ctx, j, cid)) / float64(jCidOutStake)
+			ksum = prevrank[j]*weight + ksum //force no-fma here by explicit conversion
+		}
+		rank[cid] = ksum * dampingFactor
+	}
+
+	teleport := (1.0 - dampingFactor + dampingFactor*danglingRank) * seedShare
+	for _, seed := range seeds {
+		rank[seed] += teleport
+	}
+
+	return rank
+}
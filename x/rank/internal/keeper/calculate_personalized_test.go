@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	cbd "github.com/cybercongress/go-cyber/types"
+	"github.com/cybercongress/go-cyber/x/link"
+	"github.com/cybercongress/go-cyber/x/rank/internal/types"
+)
+
+type testGraph struct {
+	types.LinkIndexedKeeper
+
+	cidsCount uint64
+	inLinks   link.Links
+	outLinks  link.Links
+	stakes    map[cbd.AccNumber]uint64
+}
+
+func newTestGraph(cidsCount uint64, stakes map[cbd.AccNumber]uint64, links ...link.CompactLink) testGraph {
+	graph := testGraph{cidsCount: cidsCount, inLinks: make(link.Links), outLinks: make(link.Links), stakes: stakes}
+	for _, l := range links {
+		graph.outLinks.Put(l.From(), l.To(), l.Acc(), 1)
+		graph.inLinks.Put(l.To(), l.From(), l.Acc(), 1)
+	}
+	return graph
+}
+
+func (g testGraph) GetOutLinks() link.Links                  { return g.outLinks }
+func (g testGraph) GetInLinks() link.Links                   { return g.inLinks }
+func (g testGraph) GetLinksCount(sdk.Context) uint64         { return 0 }
+func (g testGraph) GetCidsCount(sdk.Context) uint64          { return g.cidsCount }
+func (g testGraph) GetTotalStakes() map[cbd.AccNumber]uint64 { return g.stakes }
+func (g testGraph) FixUserStake(sdk.Context) bool            { return false }
+
+// 0 -> 1 -> 2 -> 0 cycle linked by neurons 1 and 2, cid 3 has no links
+func testCycleGraph() testGraph {
+	return newTestGraph(
+		4, map[cbd.AccNumber]uint64{1: 10, 2: 10},
+		link.NewLink(0, 1, 1), link.NewLink(1, 2, 1), link.NewLink(2, 0, 2),
+	)
+}
+
+func TestCollectSeeds(t *testing.T) {
+	graph := testCycleGraph()
+	keeper := StateKeeper{cidNumKeeper: graph, linkIndexedKeeper: graph}
+	ctx := sdk.NewContext(nil, abci.Header{}, false, nil)
+
+	// unknown cids are skipped, cids linked by neurons are added
+	require.Equal(t, []link.CidNumber{3}, keeper.collectSeeds(ctx, []link.CidNumber{3, 7, 3}, nil))
+	require.Equal(t, []link.CidNumber{0, 2, 3}, keeper.collectSeeds(ctx, []link.CidNumber{3}, []cbd.AccNumber{2}))
+	require.Equal(t, []link.CidNumber{0, 1, 2}, keeper.collectSeeds(ctx, nil, []cbd.AccNumber{1, 5}))
+	require.Empty(t, keeper.collectSeeds(ctx, []link.CidNumber{4}, []cbd.AccNumber{5}))
+}
+
+func TestPersonalizedRank(t *testing.T) {
+	graph := testCycleGraph()
+	ctx := sdk.NewContext(nil, abci.Header{Height: 1}, false, nil)
+	calcCtx := types.NewCalcContext(ctx, graph, graph, graph, false, 0.85, 0.000001, 0, 0)
+
+	// rank mass is kept by cycle and spread from seed
+	values, steps := calculatePersonalizedRankCPU(calcCtx, []link.CidNumber{0}, 1000)
+	require.True(t, steps < 1000)
+	require.InDelta(t, 1, values[0]+values[1]+values[2]+values[3], 0.00001)
+	require.True(t, values[0] > values[1] && values[1] > values[2])
+	require.Zero(t, values[3])
+
+	// rank of dangling seed is returned to it
+	values, _ = calculatePersonalizedRankCPU(calcCtx, []link.CidNumber{3}, 1000)
+	require.Equal(t, []float64{0, 0, 0, 1}, values)
+
+	// iterations are bounded
+	_, steps = calculatePersonalizedRankCPU(calcCtx, []link.CidNumber{0}, 3)
+	require.Equal(t, 3, steps)
+
+	values, steps = calculatePersonalizedRankCPU(calcCtx, nil, 1000)
+	require.Empty(t, values)
+	require.Zero(t, steps)
+}
@@ -1,7 +1,10 @@
 package keeper
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
+	"math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -12,6 +15,12 @@ import (
 func RegisterInvariants(ir sdk.InvariantRegistry, k exported.StateKeeper) {
 	ir.RegisterRoute(types.ModuleName, "index-error",
 		IndexErrorInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "cids-count",
+		CidsCountInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "merkle-tree",
+		MerkleTreeInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "rank-sum",
+		RankSumInvariant(k))
 }
 
 func IndexErrorInvariant(keeper exported.StateKeeper) sdk.Invariant {
@@ -27,3 +36,73 @@ func IndexErrorInvariant(keeper exported.StateKeeper) sdk.Invariant {
 		return sdk.FormatInvariant(types.ModuleName, "index error", msg), broken
 	}
 }
+
+// Network rank should be extended with all cids added before current block.
+func CidsCountInvariant(keeper exported.StateKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		// genesis cids are added to network rank at the end of first block
+		if ctx.BlockHeight() > 1 {
+			networkCidCount := keeper.GetNetworkCidCount()
+			cidsCount := keeper.GetCidsCountBeforeBlock(ctx)
+			if networkCidCount != cidsCount {
+				msg = fmt.Sprintf("network rank cids count %d doesn't match cids count %d", networkCidCount, cidsCount)
+				broken = true
+			}
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "cids count", msg), broken
+	}
+}
+
+// Stored merkle subtrees roots should re-hash to network rank hash.
+func MerkleTreeInvariant(keeper exported.StateKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		storedHash := keeper.GetStoredNetworkRankHash(ctx)
+		networkHash := keeper.GetNetworkRankHash()
+		if !bytes.Equal(storedHash, networkHash) {
+			msg = fmt.Sprintf(
+				"stored merkle tree hash %s doesn't match network rank hash %s",
+				hex.EncodeToString(storedHash), hex.EncodeToString(networkHash),
+			)
+			broken = true
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "merkle tree", msg), broken
+	}
+}
+
+// Every cid has at least (1-d)/N rank and each cid spreads not more than d of its rank,
+// so for any damping factor d in (0, 1) the sum of rank values lays in [1-d, 1+d] ⊂ (0, 2).
+// Cids added after rank calculation have zero rank and don't affect the sum.
+func RankSumInvariant(keeper exported.StateKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+
+		values := keeper.GetNetworkRankValues()
+		if len(values) != 0 {
+			sum := float64(0)
+			for i, value := range values {
+				if value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+					msg = fmt.Sprintf("invalid rank value %v of cid number %d", value, i)
+					broken = true
+					break
+				}
+				sum += value
+			}
+
+			if !broken && (sum <= 0 || sum >= 2) {
+				msg = fmt.Sprintf("rank values sum %v is out of (0, 2) bound", sum)
+				broken = true
+			}
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "rank sum", msg), broken
+	}
+}
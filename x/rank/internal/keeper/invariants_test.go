@@ -0,0 +1,80 @@
+package keeper
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cybercongress/go-cyber/x/rank/exported"
+)
+
+// implements only state keeper methods used by invariants
+type testStateKeeper struct {
+	exported.StateKeeper
+
+	indexError      error
+	networkCidCount uint64
+	cidsCount       uint64
+	rankValues      []float64
+	networkRankHash []byte
+	storedRankHash  []byte
+}
+
+func (k testStateKeeper) GetIndexError() error                        { return k.indexError }
+func (k testStateKeeper) GetNetworkCidCount() uint64                  { return k.networkCidCount }
+func (k testStateKeeper) GetCidsCountBeforeBlock(sdk.Context) uint64  { return k.cidsCount }
+func (k testStateKeeper) GetNetworkRankValues() []float64             { return k.rankValues }
+func (k testStateKeeper) GetNetworkRankHash() []byte                  { return k.networkRankHash }
+func (k testStateKeeper) GetStoredNetworkRankHash(sdk.Context) []byte { return k.storedRankHash }
+
+func invariantBroken(invariant sdk.Invariant, height int64) bool {
+	ctx := sdk.NewContext(nil, abci.Header{Height: height}, false, nil)
+	_, broken := invariant(ctx)
+	return broken
+}
+
+func TestIndexErrorInvariant(t *testing.T) {
+	require.False(t, invariantBroken(IndexErrorInvariant(testStateKeeper{}), 2))
+	require.True(t, invariantBroken(IndexErrorInvariant(testStateKeeper{indexError: errors.New("failed")}), 2))
+}
+
+func TestCidsCountInvariant(t *testing.T) {
+	require.False(t, invariantBroken(CidsCountInvariant(testStateKeeper{networkCidCount: 5, cidsCount: 5}), 2))
+	require.True(t, invariantBroken(CidsCountInvariant(testStateKeeper{networkCidCount: 4, cidsCount: 5}), 2))
+	// genesis cids are not added to network rank yet
+	require.False(t, invariantBroken(CidsCountInvariant(testStateKeeper{networkCidCount: 0, cidsCount: 5}), 1))
+}
+
+func TestMerkleTreeInvariant(t *testing.T) {
+	keeper := testStateKeeper{networkRankHash: []byte{1, 2}, storedRankHash: []byte{1, 2}}
+	require.False(t, invariantBroken(MerkleTreeInvariant(keeper), 2))
+
+	keeper.storedRankHash = []byte{1, 3}
+	require.True(t, invariantBroken(MerkleTreeInvariant(keeper), 2))
+}
+
+func TestRankSumInvariant(t *testing.T) {
+	cases := []struct {
+		values []float64
+		broken bool
+	}{
+		{nil, false}, // rank wasn't calculated since node start
+		{[]float64{0.5, 0.3, 0.2, 0}, false},
+		{[]float64{0.05, 0.05}, false},
+		{[]float64{1.5, 0.4}, false},
+		{[]float64{0, 0}, true},
+		{[]float64{1.5, 0.5}, true},
+		{[]float64{1.2, -0.1}, true},
+		{[]float64{0.5, math.NaN()}, true},
+		{[]float64{0.5, math.Inf(1)}, true},
+	}
+
+	for _, c := range cases {
+		keeper := testStateKeeper{rankValues: c.values}
+		require.Equal(t, c.broken, invariantBroken(RankSumInvariant(keeper), 2), "values %v", c.values)
+	}
+}
@@ -1,15 +1,19 @@
 package keeper
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 
 	"github.com/cybercongress/go-cyber/merkle"
 	"github.com/cybercongress/go-cyber/store"
+	cbd "github.com/cybercongress/go-cyber/types"
 	"github.com/cybercongress/go-cyber/x/bank"
 	"github.com/cybercongress/go-cyber/x/link"
 	"github.com/cybercongress/go-cyber/x/rank/internal/types"
+	"sort"
 	"strconv"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -24,6 +28,8 @@ type StateKeeper struct {
 
 	networkCidRank types.Rank // array linksIndex is cid number
 	nextCidRank    types.Rank // array linksIndex is cid number
+	// network rank replaced at last rank round, its tree is reused by next calculation
+	spareCidRank   types.Rank
 
 	rankCalculationFinished bool
 	cidCount                int64
@@ -42,17 +48,35 @@ type StateKeeper struct {
 	linkIndexedKeeper types.LinkIndexedKeeper
 	paramSpace 		  params.Subspace
 
+	// network rank values and full tree on disk, nil if rank proofs serving is disabled
+	proofTrees        *types.ProofTreeStore
+
 	// index
 	index         	  types.SearchIndex
 	getIndexError     types.GetError
+
+	// personalized rank
+	personalizedConfig types.PersonalizedRankConfig
+	personalizedSlots  chan struct{}
+	// guards in-memory links and stakes used by personalized rank from modification at rank round start
+	graphLock          *sync.RWMutex
+	// guards network rank tree read by rpc from its replacement and extension at the end of block,
+	// so replaced tree is unreachable by readers when it's reused by next calculation
+	treeLock           *sync.RWMutex
+	// height of block network rank tree was produced at the end of, guarded by treeLock
+	networkTreeHeight  int64
 }
 
 func NewStateKeeper(
 	cdc *codec.Codec, paramSpace params.Subspace, allowSearch bool,
 	mainKeeper store.MainKeeper, stakeIndex bank.IndexedKeeper,
 	linkIndexedKeeper types.LinkIndexedKeeper, cidNumKeeper types.CidNumberKeeper,
-	unit types.ComputeUnit,
+	unit types.ComputeUnit, personalizedConfig types.PersonalizedRankConfig, proofTreesDir string,
 ) *StateKeeper {
+	var proofTrees *types.ProofTreeStore
+	if proofTreesDir != "" {
+		proofTrees = types.NewProofTreeStore(proofTreesDir)
+	}
 	return &StateKeeper{
 		cdc:            cdc,
 		paramSpace: 	paramSpace.WithKeyTable(types.ParamKeyTable()),
@@ -66,13 +90,30 @@ func NewStateKeeper(
 		cidNumKeeper:   cidNumKeeper,
 		computeUnit:    unit,
 		hasNewLinksForPeriod: true,
+		personalizedConfig: personalizedConfig,
+		personalizedSlots: make(chan struct{}, personalizedConfig.MaxConcurrency),
+		graphLock:      new(sync.RWMutex),
+		treeLock:       new(sync.RWMutex),
+		proofTrees:     proofTrees,
 	}
 }
 
 func (s *StateKeeper) Load(ctx sdk.Context, log log.Logger) {
-	s.networkCidRank = types.NewFromMerkle(s.mainKeeper.GetCidsCount(ctx), s.mainKeeper.GetLatestMerkleTree(ctx))
-	s.nextCidRank = types.NewFromMerkle(s.mainKeeper.GetNextRankCidCount(ctx), s.mainKeeper.GetNextMerkleTree(ctx))
+	s.networkCidRank = types.NewFromMerkle(
+		s.mainKeeper.GetCidsCount(ctx), s.mainKeeper.GetLatestMerkleTree(ctx), s.mainKeeper.GetLatestMerkleTreeVersion(ctx),
+	)
+	s.nextCidRank = types.NewFromMerkle(
+		s.mainKeeper.GetNextRankCidCount(ctx), s.mainKeeper.GetNextMerkleTree(ctx), s.mainKeeper.GetNextMerkleTreeVersion(ctx),
+	)
 	s.cidCount = int64(s.mainKeeper.GetCidsCount(ctx))
+	s.networkTreeHeight = ctx.BlockHeight()
+
+	if s.proofTrees != nil {
+		err := s.proofTrees.Load(s.networkCidRank.MerkleTree.RootHash(), s.networkCidRank.CidCount)
+		if err != nil {
+			log.Info("Rank proofs are not available till next rank", "reason", err.Error())
+		}
+	}
 
 	s.index = s.BuildSearchIndex(log)
 	s.index.Load(s.linkIndexedKeeper.GetOutLinks()) // was wrong with GetNextOutLinks
@@ -92,7 +133,16 @@ func (s *StateKeeper) Load(ctx sdk.Context, log log.Logger) {
 
 	// if we fell down and need to start new rank calculation
 	if !s.mainKeeper.GetRankCalculationFinished(ctx) {
-		s.startRankCalculation(ctx, dampingFactor, tolerance, log)
+		// links ages are counted from the height calculation was started at
+		roundBlockNumber := (ctx.BlockHeight() / params.CalculationPeriod) * params.CalculationPeriod
+		if roundBlockNumber == 0 {
+			roundBlockNumber = 1 // special case cause tendermint blocks start from 1
+		}
+		// tree version is fixed at calculation start and could differ from current one after upgrade
+		s.startRankCalculation(
+			ctx.WithBlockHeight(roundBlockNumber), dampingFactor, tolerance, params.LinkWeightHalfLife,
+			s.mainKeeper.GetNextMerkleTreeVersion(ctx), log,
+		)
 		s.rankCalculationFinished = false
 	}
 }
@@ -110,12 +160,18 @@ func (s *StateKeeper) EndBlocker(ctx sdk.Context, log log.Logger) {
 
 	s.index.PutNewLinks(s.linkIndexedKeeper.GetCurrentBlockNewLinks())
 
-	blockHasNewLinks := s.linkIndexedKeeper.EndBlocker()
+	blockHasNewLinks := s.linkIndexedKeeper.EndBlocker(ctx)
 	s.hasNewLinksForPeriod = s.hasNewLinksForPeriod || blockHasNewLinks
 
 	params := s.GetParams(ctx)
+	rankRound := ctx.BlockHeight()%params.CalculationPeriod == 0 || ctx.BlockHeight() == 1
+
+	s.checkRankCalcFinished(ctx, rankRound, log)
 
-	if ctx.BlockHeight()%params.CalculationPeriod == 0 || ctx.BlockHeight() == 1 {
+	// network rank tree is replaced and extended under single lock,
+	// so rpc readers get only trees produced at the end of block
+	s.treeLock.Lock()
+	if rankRound {
 
 		dampingFactor, err := strconv.ParseFloat(params.DampingFactor.String(), 64)
 		if err != nil {
@@ -127,25 +183,47 @@ func (s *StateKeeper) EndBlocker(ctx sdk.Context, log log.Logger) {
 			panic(err)
 		}
 
-		s.checkRankCalcFinished(ctx, true, log)
-		s.applyNextRank()
+		s.applyNextRank(ctx, log)
 
 		s.cidCount = int64(currentCidsCount)
+		s.graphLock.Lock()
 		stakeChanged := s.stakeKeeper.FixUserStake(ctx)
+		if s.hasNewLinksForPeriod || stakeChanged {
+			s.linkIndexedKeeper.FixLinks()
+		}
+		s.graphLock.Unlock()
 
 		// start new calculation
 		if s.hasNewLinksForPeriod || stakeChanged {
-			s.linkIndexedKeeper.FixLinks()
 			s.rankCalculationFinished = false
 			s.hasNewLinksForPeriod = false
 			s.mainKeeper.StoreRankCalculationFinished(ctx, false)
-			s.startRankCalculation(ctx, dampingFactor, tolerance, log)
+			s.mainKeeper.StoreNextRankCalcHeight(ctx, ctx.BlockHeight())
+			treeVersion := s.mainKeeper.GetRankTreeVersion(ctx)
+			if treeVersion != merkle.LegacyVersion {
+				s.mainKeeper.StoreNextMerkleTreeVersion(ctx, treeVersion)
+			}
+			s.startRankCalculation(ctx, dampingFactor, tolerance, params.LinkWeightHalfLife, treeVersion, log)
 		}
-	} else {
-		s.checkRankCalcFinished(ctx, false, log)
 	}
 	s.networkCidRank.AddNewCids(currentCidsCount)
+	if s.proofTrees != nil {
+		if err := s.proofTrees.AddNewCids(currentCidsCount); err != nil {
+			log.Error("Rank proofs are not available till next rank", "reason", err.Error())
+		}
+	}
+	s.networkTreeHeight = ctx.BlockHeight()
+	s.treeLock.Unlock()
 	s.mainKeeper.StoreLatestMerkleTree(ctx, s.getNetworkMerkleTreeAsBytes())
+	if treeVersion := s.networkCidRank.MerkleTree.Version(); treeVersion != merkle.LegacyVersion {
+		s.mainKeeper.StoreLatestMerkleTreeVersion(ctx, treeVersion)
+	}
+}
+
+// Switches merkle trees of rank calculations started from now on to given hashing version.
+// Network rank tree keeps its version till next calculated rank is applied.
+func (s *StateKeeper) SetTreeVersion(ctx sdk.Context, version merkle.Version) {
+	s.mainKeeper.StoreRankTreeVersion(ctx, version)
 }
 
 func (s *StateKeeper) Search(cidNumber link.CidNumber, page, perPage int) ([]types.RankedCidNumber, int, error) {
@@ -157,14 +235,191 @@ func (s *StateKeeper) Top(page, perPage int) ([]types.RankedCidNumber, int, erro
 }
 
 func (s *StateKeeper) GetRankValue(cidNumber link.CidNumber) float64 {
+	// without search index rank values are read from rank proofs files
+	if !s.allowSearch && s.proofTrees != nil {
+		value, _ := s.proofTrees.GetRankValue(cidNumber)
+		return value
+	}
 	return s.index.GetRankValue(cidNumber)
 }
 
-func (s *StateKeeper) startRankCalculation(ctx sdk.Context, dampingFactor float64, tolerance float64, log log.Logger) {
+// Returns network rank values of given cids and requested proofs of them taken from the same network rank tree,
+// along with tree parameters required to verify proofs and height of block tree was produced at.
+func (s *StateKeeper) GetRankSnapshot(
+	cidNumbers []link.CidNumber, proofs types.SnapshotProofs,
+) (snapshot types.RankSnapshot, err error) {
+
+	s.treeLock.RLock()
+	defer s.treeLock.RUnlock()
+
+	tree := s.networkCidRank.MerkleTree
+	snapshot = types.RankSnapshot{
+		Values:      make([]float64, len(cidNumbers)),
+		LeavesCount: tree.LeavesCount(),
+		TreeVersion: tree.Version(),
+		Height:      s.networkTreeHeight,
+	}
+
+	indices := make([]int, len(cidNumbers))
+	for i, cidNumber := range cidNumbers {
+		if int(cidNumber) >= snapshot.LeavesCount {
+			return types.RankSnapshot{}, types.ErrCidNotRanked
+		}
+		if snapshot.Values[i], err = s.getNetworkRankValue(cidNumber); err != nil {
+			return types.RankSnapshot{}, err
+		}
+		indices[i] = int(cidNumber)
+	}
+
+	switch proofs {
+	case types.WithIndexProofs:
+		if len(cidNumbers) != 1 {
+			return types.RankSnapshot{}, errors.New("index proofs are taken for single cid")
+		}
+		snapshot.Proofs, err = s.getIndexProofs(cidNumbers[0])
+	case types.WithMultiProof:
+		snapshot.MultiProof, err = s.getMultiProof(indices)
+	}
+	if err != nil {
+		return types.RankSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Network rank values are kept in memory since first applied rank, and in rank proofs files if enabled.
+func (s *StateKeeper) getNetworkRankValue(cidNumber link.CidNumber) (float64, error) {
+	if s.networkCidRank.Values != nil {
+		return s.networkCidRank.Values[cidNumber], nil
+	}
+	if s.proofTrees != nil && s.proofTrees.Available() {
+		return s.proofTrees.GetRankValue(cidNumber)
+	}
+	return s.index.GetRankValue(cidNumber), nil
+}
 
+// Returns proofs of network rank element, in-memory full tree is used if available.
+func (s *StateKeeper) getIndexProofs(cidNumber link.CidNumber) ([]merkle.Proof, error) {
+	if tree := s.networkCidRank.MerkleTree; tree.IsFull() {
+		return tree.GetIndexProofs(int(cidNumber)), nil
+	}
+	if s.proofTrees != nil {
+		return s.proofTrees.GetIndexProofs(cidNumber)
+	}
+	return nil, types.ErrProofsNotAvailable
+}
 
-	calcCtx := types.NewCalcContext(ctx, s.linkIndexedKeeper, s.cidNumKeeper, s.stakeKeeper, s.allowSearch, dampingFactor, tolerance)
-	go CalculateRankInParallel(calcCtx, s.rankCalcChan, s.rankErrChan, s.computeUnit, log)
+// Returns multiproof of network rank elements, in-memory full tree is used if available.
+func (s *StateKeeper) getMultiProof(indices []int) (*merkle.MultiProof, error) {
+	if tree := s.networkCidRank.MerkleTree; tree.IsFull() {
+		if multiProof := tree.GetMultiProof(indices); multiProof != nil {
+			return multiProof, nil
+		}
+		return nil, types.ErrProofsNotAvailable
+	}
+	if s.proofTrees != nil {
+		return s.proofTrees.GetMultiProof(indices)
+	}
+	return nil, types.ErrProofsNotAvailable
+}
+
+// Calculates personalized rank for given seed cids and cids linked by given neurons.
+// Runs on CPU over current rank round links and stakes. Returns top cids and iterations performed.
+func (s *StateKeeper) PersonalizedRank(
+	ctx sdk.Context, cids []link.CidNumber, neurons []cbd.AccNumber, topK int,
+) ([]types.RankedCidNumber, int, error) {
+
+	if !s.personalizedConfig.Enabled() {
+		return nil, 0, errors.New("personalized rank is not enabled on this node")
+	}
+
+	select {
+	case s.personalizedSlots <- struct{}{}:
+		defer func() { <-s.personalizedSlots }()
+	default:
+		return nil, 0, errors.New("too many personalized rank calculations in progress, try later")
+	}
+
+	s.graphLock.RLock()
+	defer s.graphLock.RUnlock()
+
+	seeds := s.collectSeeds(ctx, cids, neurons)
+	if len(seeds) == 0 {
+		return nil, 0, errors.New("no known seed cids found")
+	}
+
+	params := s.GetParams(ctx)
+
+	dampingFactor, err := strconv.ParseFloat(params.DampingFactor.String(), 64)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tolerance, err := strconv.ParseFloat(params.Tolerance.String(), 64)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	calcCtx := types.NewCalcContext(
+		ctx, s.linkIndexedKeeper, s.cidNumKeeper, s.stakeKeeper, false, dampingFactor, tolerance, params.LinkWeightHalfLife,
+		s.mainKeeper.GetRankTreeVersion(ctx),
+	)
+	values, steps := calculatePersonalizedRankCPU(calcCtx, seeds, s.personalizedConfig.MaxIterations)
+
+	return types.BuildTopK(values, topK), steps, nil
+}
+
+// Returns sorted unique seed cids: given cids plus all cids linked by given neurons.
+func (s *StateKeeper) collectSeeds(ctx sdk.Context, cids []link.CidNumber, neurons []cbd.AccNumber) []link.CidNumber {
+	cidsCount := link.CidNumber(s.cidNumKeeper.GetCidsCount(ctx))
+	seedsSet := make(map[link.CidNumber]struct{})
+
+	for _, cid := range cids {
+		if cid < cidsCount {
+			seedsSet[cid] = struct{}{}
+		}
+	}
+
+	if len(neurons) != 0 {
+		neuronsSet := make(map[cbd.AccNumber]struct{}, len(neurons))
+		for _, neuron := range neurons {
+			neuronsSet[neuron] = struct{}{}
+		}
+
+		for from, toCids := range s.linkIndexedKeeper.GetOutLinks() {
+			for to, accs := range toCids {
+				for acc := range accs {
+					if _, ok := neuronsSet[acc]; ok {
+						seedsSet[from] = struct{}{}
+						seedsSet[to] = struct{}{}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	seeds := make([]link.CidNumber, 0, len(seedsSet))
+	for seed := range seedsSet {
+		seeds = append(seeds, seed)
+	}
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i] < seeds[j] })
+
+	return seeds
+}
+
+func (s *StateKeeper) startRankCalculation(
+	ctx sdk.Context, dampingFactor float64, tolerance float64, linkWeightHalfLife int64,
+	treeVersion merkle.Version, log log.Logger,
+) {
+
+	calcCtx := types.NewCalcContext(
+		ctx, s.linkIndexedKeeper, s.cidNumKeeper, s.stakeKeeper, s.allowSearch, dampingFactor, tolerance, linkWeightHalfLife,
+		treeVersion,
+	)
+	// replaced network rank is not served anymore, so its tree could be updated by calculation
+	calcCtx.PreviousRank = s.spareCidRank
+	s.spareCidRank = types.Rank{}
+	go CalculateRankInParallel(calcCtx, s.rankCalcChan, s.rankErrChan, s.computeUnit, s.proofTrees, log)
 }
 
 func (s *StateKeeper) checkRankCalcFinished(ctx sdk.Context, block bool, log log.Logger) {
@@ -197,11 +452,23 @@ func (s *StateKeeper) handleNextRank(ctx sdk.Context, newRank types.Rank) {
 	s.mainKeeper.StoreRankCalculationFinished(ctx, true)
 }
 
-func (s *StateKeeper) applyNextRank() {
+// Should be called under treeLock.
+func (s *StateKeeper) applyNextRank(ctx sdk.Context, log log.Logger) {
 
 	if !s.nextCidRank.IsEmpty() {
+		s.spareCidRank = s.networkCidRank
 		s.networkCidRank = s.nextCidRank
+		if calcHeight := s.mainKeeper.GetNextRankCalcHeight(ctx); calcHeight != 0 {
+			s.mainKeeper.StoreLatestRankCalcHeight(ctx, calcHeight)
+		}
 		s.index.PutNewRank(s.networkCidRank)
+
+		if s.proofTrees != nil {
+			err := s.proofTrees.ApplyNext(s.networkCidRank.MerkleTree.RootHash(), s.networkCidRank.CidCount)
+			if err != nil {
+				log.Error("Rank proofs are not available till next rank", "reason", err.Error())
+			}
+		}
 	}
 	s.nextCidRank.Clear()
 }
@@ -222,12 +489,41 @@ func (s *StateKeeper) getNextMerkleTreeAsBytes() []byte {
 	return s.nextCidRank.MerkleTree.ExportSubtreesRoots()
 }
 
-func (s *StateKeeper) GetLastCidNum() link.CidNumber {
-	return link.CidNumber(len(s.networkCidRank.Values) - 1)
+func (s *StateKeeper) GetNetworkCidCount() uint64 {
+	return s.networkCidRank.CidCount
+}
+
+// Returns nil if network rank wasn't calculated since node start.
+func (s *StateKeeper) GetNetworkRankValues() []float64 {
+	return s.networkCidRank.Values
+}
+
+// Returns root hash of network merkle tree restored from stored subtrees roots.
+func (s *StateKeeper) GetStoredNetworkRankHash(ctx sdk.Context) []byte {
+	return types.NewFromMerkle(
+		s.mainKeeper.GetCidsCount(ctx), s.mainKeeper.GetLatestMerkleTree(ctx), s.mainKeeper.GetLatestMerkleTreeVersion(ctx),
+	).MerkleTree.RootHash()
+}
+
+// Returns cids count without cids added by links of current (not ended) block.
+// Network rank is extended with new cids only at the end of block.
+func (s *StateKeeper) GetCidsCountBeforeBlock(ctx sdk.Context) uint64 {
+	cidsCount := s.mainKeeper.GetCidsCount(ctx)
+
+	newCids := make(map[link.CidNumber]struct{})
+	for _, l := range s.linkIndexedKeeper.GetCurrentBlockNewLinks() {
+		for _, number := range []link.CidNumber{l.From(), l.To()} {
+			if uint64(number) >= s.networkCidRank.CidCount {
+				newCids[number] = struct{}{}
+			}
+		}
+	}
+
+	return cidsCount - uint64(len(newCids))
 }
 
-func (s *StateKeeper) GetMerkleTree() *merkle.Tree {
-	return s.networkCidRank.MerkleTree
+func (s *StateKeeper) GetLastCidNum() link.CidNumber {
+	return link.CidNumber(len(s.networkCidRank.Values) - 1)
 }
 
 func (s *StateKeeper) GetIndexError() error {
@@ -235,7 +531,11 @@ func (s *StateKeeper) GetIndexError() error {
 }
 
 func (s *StateKeeper) GetParams(ctx sdk.Context) (params types.Params) {
-	s.paramSpace.GetParamSet(ctx, &params)
+	s.paramSpace.Get(ctx, types.KeyCalculationPeriod, &params.CalculationPeriod)
+	s.paramSpace.Get(ctx, types.KeyDampingFactor, &params.DampingFactor)
+	s.paramSpace.Get(ctx, types.KeyTolerance, &params.Tolerance)
+	// absent in state of chains started before links weights decay, zero disables decay
+	s.paramSpace.GetIfExists(ctx, types.KeyLinkWeightHalfLife, &params.LinkWeightHalfLife)
 	return params
 }
 
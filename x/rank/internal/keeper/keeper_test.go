@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cybercongress/go-cyber/merkle"
+	"github.com/cybercongress/go-cyber/x/link"
+	"github.com/cybercongress/go-cyber/x/rank/internal/types"
+)
+
+func TestGetRankSnapshot(t *testing.T) {
+	values := []float64{0.4, 0.3, 0.2, 0.1}
+	keeper := StateKeeper{
+		networkCidRank:    types.NewRank(values, log.NewNopLogger(), true, merkle.DomainSeparatedVersion),
+		networkTreeHeight: 7,
+		treeLock:          new(sync.RWMutex),
+	}
+	rootHash := keeper.GetNetworkRankHash()
+
+	snapshot, err := keeper.GetRankSnapshot([]link.CidNumber{2}, types.WithIndexProofs)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.2}, snapshot.Values)
+	require.Equal(t, int64(7), snapshot.Height)
+	require.Equal(t, merkle.DomainSeparatedVersion, snapshot.TreeVersion)
+	require.Equal(t, len(values), snapshot.LeavesCount)
+
+	leaf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(leaf, math.Float64bits(snapshot.Values[0]))
+	require.True(t, merkle.VerifyProof(
+		sha256.New(), snapshot.TreeVersion, rootHash, 2, snapshot.LeavesCount, leaf, snapshot.Proofs,
+	))
+
+	snapshot, err = keeper.GetRankSnapshot([]link.CidNumber{3, 0}, types.WithMultiProof)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.4}, snapshot.Values)
+	require.NotNil(t, snapshot.MultiProof)
+	require.Nil(t, snapshot.Proofs)
+
+	_, err = keeper.GetRankSnapshot([]link.CidNumber{4}, types.WithoutProofs)
+	require.Equal(t, types.ErrCidNotRanked, err)
+	_, err = keeper.GetRankSnapshot([]link.CidNumber{0, 1}, types.WithIndexProofs)
+	require.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/cybercongress/go-cyber/merkle"
 	. "github.com/cybercongress/go-cyber/types"
 	"github.com/cybercongress/go-cyber/x/link"
 )
@@ -19,14 +20,23 @@ type CalculationContext struct {
 	stakes map[AccNumber]uint64
 
 	FullTree bool
+	// hashing version of rank merkle tree, fixed at calculation start
+	TreeVersion merkle.Version
+	// rank replaced by network one at calculation start, its tree is reused for new rank if possible
+	PreviousRank Rank
 
 	DampingFactor float64
 	Tolerance 	  float64
+
+	// links weights decay, links age is counted from calculation start height
+	Height             int64
+	LinkWeightHalfLife int64
 }
 
 func NewCalcContext(
 	ctx sdk.Context, linkIndex LinkIndexedKeeper, numberKeeper CidNumberKeeper,
-	stakeKeeper StakeKeeper, fullTree bool, dampingFactor float64, tolerance float64) *CalculationContext {
+	stakeKeeper StakeKeeper, fullTree bool, dampingFactor float64, tolerance float64,
+	linkWeightHalfLife int64, treeVersion merkle.Version) *CalculationContext {
 
 	return &CalculationContext{
 		CidsCount:  int64(numberKeeper.GetCidsCount(ctx)),
@@ -38,9 +48,13 @@ func NewCalcContext(
 		stakes: stakeKeeper.GetTotalStakes(),
 
 		FullTree: fullTree,
+		TreeVersion: treeVersion,
 
 		DampingFactor: dampingFactor,
 		Tolerance: tolerance,
+
+		Height:             ctx.BlockHeight(),
+		LinkWeightHalfLife: linkWeightHalfLife,
 	}
 }
 
@@ -60,6 +74,11 @@ func (c *CalculationContext) GetStakes() map[AccNumber]uint64 {
 	return c.stakes
 }
 
+// Returns stake of given account for link created at given height.
+func (c *CalculationContext) GetLinkStake(acc AccNumber, height uint64) uint64 {
+	return DecayedStake(c.stakes[acc], c.Height-int64(height), c.LinkWeightHalfLife)
+}
+
 func (c *CalculationContext) GetTolerance() float64 {
 	return c.Tolerance
 }
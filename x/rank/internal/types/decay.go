@@ -0,0 +1,26 @@
+package types
+
+import "math/bits"
+
+// Returns link stake decayed by link age. Stake halves every halfLife blocks
+// and decays linearly between halvings. Uses integer math only, so CPU and GPU
+// calculations get exactly the same link stakes. Zero halfLife disables decay.
+func DecayedStake(stake uint64, age int64, halfLife int64) uint64 {
+	if halfLife <= 0 || age <= 0 {
+		return stake
+	}
+
+	halvings := age / halfLife
+	if halvings >= 64 {
+		return 0
+	}
+
+	current := stake >> uint(halvings)
+	next := current >> 1
+
+	// (current - next) * rem / halfLife < current - next, so 128 bit quotient fits into uint64
+	hi, lo := bits.Mul64(current-next, uint64(age%halfLife))
+	decrease, _ := bits.Div64(hi, lo, uint64(halfLife))
+
+	return current - decrease
+}
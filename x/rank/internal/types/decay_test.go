@@ -0,0 +1,67 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/cybercongress/go-cyber/types"
+)
+
+func TestDecayedStake(t *testing.T) {
+	cases := []struct {
+		stake    uint64
+		age      int64
+		halfLife int64
+		expected uint64
+	}{
+		{1000, 0, 100, 1000},
+		{1000, -5, 100, 1000}, // links imported at genesis could be created at greater heights
+		{1000, 5000, 0, 1000}, // decay disabled
+		{1000, 50, 100, 750},
+		{1000, 100, 100, 500},
+		{1000, 150, 100, 375},
+		{1000, 199, 100, 253},
+		{1000, 1000, 100, 0},
+		{5, 1, 2, 4}, // decrease is truncated
+		{1000, 63 * 100, 100, 0},
+		{math.MaxUint64, 64 * 100, 100, 0},
+		{math.MaxUint64, 63 * 100, 100, 1},
+		{math.MaxUint64, 1, 2, math.MaxUint64 - 1<<62},
+	}
+
+	for _, c := range cases {
+		require.Equal(
+			t, c.expected, DecayedStake(c.stake, c.age, c.halfLife),
+			"stake %d, age %d, half life %d", c.stake, c.age, c.halfLife,
+		)
+	}
+}
+
+func TestDecayedStakeDecreasesWithAge(t *testing.T) {
+	for _, stake := range []uint64{1, 7, 1000, math.MaxUint64} {
+		previous := stake
+		for age := int64(0); age <= 64*10; age++ {
+			decayed := DecayedStake(stake, age, 10)
+			require.True(t, decayed <= previous, "stake %d, age %d", stake, age)
+			previous = decayed
+		}
+		require.Zero(t, previous)
+	}
+}
+
+// Links imported at genesis keep heights of chain they were exported from,
+// links above current height aren't decayed till chain reaches their heights.
+func TestLinkStakeOfImportedLinks(t *testing.T) {
+	ctx := CalculationContext{
+		stakes:             map[AccNumber]uint64{1: 1000},
+		Height:             100,
+		LinkWeightHalfLife: 100,
+	}
+
+	require.Equal(t, uint64(1000), ctx.GetLinkStake(1, 5000))
+	require.Equal(t, uint64(1000), ctx.GetLinkStake(1, 100))
+	require.Equal(t, uint64(750), ctx.GetLinkStake(1, 50))
+	require.Equal(t, uint64(500), ctx.GetLinkStake(1, 0)) // legacy links without heights
+}
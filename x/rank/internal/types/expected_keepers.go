@@ -14,7 +14,7 @@ type StakeKeeper interface {
 
 type LinkIndexedKeeper interface {
 	FixLinks()
-	EndBlocker() bool
+	EndBlocker(sdk.Context) bool
 
 	GetOutLinks() link.Links
 	GetInLinks() link.Links
@@ -20,4 +20,7 @@ const (
 	QueryCalculationWindow  = "calculation_window"
 	QueryDampingFactor      = "damping_factor"
 	QueryTolerance          = "tolerance"
+
+	// upgrade switching rank merkle trees to domain separated hashing
+	TreeDomainSeparationUpgrade = "rank-tree-domain-separation"
 )
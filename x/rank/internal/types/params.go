@@ -13,6 +13,7 @@ var (
 	KeyCalculationPeriod = []byte("CalculationPeriod")
 	KeyDampingFactor     = []byte("DampingFactor")
 	KeyTolerance		 = []byte("Tolerance")
+	KeyLinkWeightHalfLife = []byte("LinkWeightHalfLife")
 )
 
 // Params defines the parameters for the rank module.
@@ -20,6 +21,8 @@ type Params struct {
 	CalculationPeriod int64   `json:"calculation_period" yaml:"calculation_period"`
 	DampingFactor 	  sdk.Dec `json:"damping_factor" yaml:"damping_factor"`
 	Tolerance		  sdk.Dec `json:"tolerance" yaml:"tolerance"`
+	// blocks after which link weight halves, 0 disables links weights decay
+	LinkWeightHalfLife int64  `json:"link_weight_half_life" yaml:"link_weight_half_life"`
 }
 
 // ParamKeyTable for rank module
@@ -32,12 +35,14 @@ func NewParams(
 	calculationPeriod int64,
 	dampingFactor sdk.Dec,
 	tolerance sdk.Dec,
+	linkWeightHalfLife int64,
 ) Params {
 
 	return Params{
 		CalculationPeriod: calculationPeriod,
 		DampingFactor:     dampingFactor,
 		Tolerance:		   tolerance,
+		LinkWeightHalfLife: linkWeightHalfLife,
 	}
 }
 
@@ -47,6 +52,7 @@ func DefaultParams() Params {
 		CalculationPeriod: int64(5),
 		DampingFactor:	   sdk.NewDecWithPrec(85, 2),
 		Tolerance:         sdk.NewDecWithPrec(1, 3),
+		LinkWeightHalfLife: int64(0),
 	}
 }
 
@@ -60,6 +66,9 @@ func (p Params) Validate() error {
 	if err := validateTolerance(p.Tolerance); err != nil {
 		return err
 	}
+	if err := validateLinkWeightHalfLife(p.LinkWeightHalfLife); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -70,8 +79,9 @@ func (p Params) String() string {
   CalculationPeriod: %d
   DampingFactor:	 %d
   Tolerance:		 %d
+  LinkWeightHalfLife: %d
 `,
-		p.CalculationPeriod, p.DampingFactor, p.Tolerance,
+		p.CalculationPeriod, p.DampingFactor, p.Tolerance, p.LinkWeightHalfLife,
 	)
 }
 
@@ -125,10 +135,25 @@ func validateTolerance(i interface{}) error {
 	return nil
 }
 
+func validateLinkWeightHalfLife(i interface{}) error {
+	v, ok := i.(int64)
+
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("link weight half life should be positive or zero: %d", v)
+	}
+
+	return nil
+}
+
 func (p *Params) ParamSetPairs() subspace.ParamSetPairs {
 	return subspace.ParamSetPairs{
 		params.NewParamSetPair(KeyCalculationPeriod, &p.CalculationPeriod, validateCalculationPeriod),
 		params.NewParamSetPair(KeyDampingFactor, &p.DampingFactor, validateDampingFactor),
 		params.NewParamSetPair(KeyTolerance, &p.Tolerance, validateTolerance),
+		params.NewParamSetPair(KeyLinkWeightHalfLife, &p.LinkWeightHalfLife, validateLinkWeightHalfLife),
 	}
 }
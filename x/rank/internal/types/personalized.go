@@ -0,0 +1,19 @@
+package types
+
+// Node-level (non consensus) limits for on-demand personalized rank calculations.
+// Zero concurrency disables personalized rank on the node.
+type PersonalizedRankConfig struct {
+	MaxIterations  int
+	MaxConcurrency int
+}
+
+func NewPersonalizedRankConfig(maxIterations, maxConcurrency int) PersonalizedRankConfig {
+	return PersonalizedRankConfig{
+		MaxIterations:  maxIterations,
+		MaxConcurrency: maxConcurrency,
+	}
+}
+
+func (c PersonalizedRankConfig) Enabled() bool {
+	return c.MaxConcurrency > 0 && c.MaxIterations > 0
+}
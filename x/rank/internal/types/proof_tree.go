@@ -0,0 +1,244 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cybercongress/go-cyber/merkle"
+	"github.com/cybercongress/go-cyber/x/link"
+)
+
+const (
+	networkValuesFile = "network_values"
+	networkTreeFile   = "network_tree"
+	nextValuesFile    = "next_values"
+	nextTreeFile      = "next_tree"
+)
+
+var ErrProofsNotAvailable = errors.New("rank proofs are not available")
+
+// Keeps values and full merkle tree of network rank in node data dir, so rank proofs could be served
+// without full in-memory tree. Files of next rank are written by rank calculation and replace network
+// ones when next rank is applied. Cids added after rank have zero rank, their elements are kept in memory.
+type ProofTreeStore struct {
+	dir string
+
+	mtx          sync.RWMutex
+	valuesFile   *os.File
+	storedValues int
+	treeFile     *os.File
+	tree         *merkle.FlatTree
+}
+
+func NewProofTreeStore(dir string) *ProofTreeStore {
+	return &ProofTreeStore{dir: dir}
+}
+
+// Writes rank values and tree as next rank files. Safe to call from calculation goroutine.
+func (s *ProofTreeStore) WriteNext(rank Rank) error {
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	ranksBytes := make([][]byte, len(rank.Values))
+	valuesBytes := make([]byte, 0, 8*len(rank.Values))
+	for i, f64 := range rank.Values {
+		ranksBytes[i] = make([]byte, 8)
+		binary.LittleEndian.PutUint64(ranksBytes[i], math.Float64bits(f64))
+		valuesBytes = append(valuesBytes, ranksBytes[i]...)
+	}
+
+	err := s.writeFile(nextValuesFile, func(f *os.File) error {
+		_, err := f.Write(valuesBytes)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.writeFile(nextTreeFile, func(f *os.File) error {
+		return merkle.WriteFlatTree(f, sha256.New(), rank.MerkleTree.Version(), ranksBytes)
+	})
+}
+
+// file is replaced only after it is completely written
+func (s *ProofTreeStore) writeFile(name string, write func(f *os.File) error) error {
+	path := filepath.Join(s.dir, name)
+	tmpPath := path + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err = write(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Replaces network rank files by next rank ones and opens them.
+// Root hash and cids count are of applied rank, files are checked against them.
+func (s *ProofTreeStore) ApplyNext(rootHash []byte, cidCount uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.close()
+
+	for _, names := range [][2]string{{nextValuesFile, networkValuesFile}, {nextTreeFile, networkTreeFile}} {
+		err := os.Rename(filepath.Join(s.dir, names[0]), filepath.Join(s.dir, names[1]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.open(rootHash, cidCount)
+}
+
+// Opens network rank files after node restart. Root hash and cids count are of network rank.
+func (s *ProofTreeStore) Load(rootHash []byte, cidCount uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.close()
+	return s.open(rootHash, cidCount)
+}
+
+func (s *ProofTreeStore) open(rootHash []byte, cidCount uint64) (err error) {
+
+	defer func() {
+		if err != nil {
+			s.close()
+		}
+	}()
+
+	if s.valuesFile, err = os.Open(filepath.Join(s.dir, networkValuesFile)); err != nil {
+		return err
+	}
+	valuesInfo, err := s.valuesFile.Stat()
+	if err != nil {
+		return err
+	}
+	s.storedValues = int(valuesInfo.Size() / 8)
+
+	if s.treeFile, err = os.Open(filepath.Join(s.dir, networkTreeFile)); err != nil {
+		return err
+	}
+	treeInfo, err := s.treeFile.Stat()
+	if err != nil {
+		return err
+	}
+	if s.tree, err = merkle.NewFlatTree(s.treeFile, treeInfo.Size(), sha256.New()); err != nil {
+		return err
+	}
+
+	if s.tree.LeavesCount() != s.storedValues || uint64(s.storedValues) > cidCount {
+		return errors.New("rank proofs files don't match network rank")
+	}
+	if err = s.addNewCids(cidCount); err != nil {
+		return err
+	}
+
+	treeRootHash, err := s.tree.RootHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(treeRootHash, rootHash) {
+		return errors.New("rank proofs tree root hash doesn't match network rank hash")
+	}
+	return nil
+}
+
+func (s *ProofTreeStore) close() {
+	if s.valuesFile != nil {
+		_ = s.valuesFile.Close()
+	}
+	if s.treeFile != nil {
+		_ = s.treeFile.Close()
+	}
+	s.valuesFile = nil
+	s.treeFile = nil
+	s.tree = nil
+	s.storedValues = 0
+}
+
+// Extends network rank tree with zero rank elements of new cids, the same as Rank.AddNewCids.
+func (s *ProofTreeStore) AddNewCids(cidCount uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.tree == nil {
+		return nil
+	}
+	if err := s.addNewCids(cidCount); err != nil {
+		s.close()
+		return err
+	}
+	return nil
+}
+
+func (s *ProofTreeStore) addNewCids(cidCount uint64) error {
+	zeroRankBytes := make([]byte, 8)
+	for i := uint64(s.tree.LeavesCount()); i < cidCount; i++ {
+		if err := s.tree.Push(zeroRankBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProofTreeStore) Available() bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.tree != nil
+}
+
+func (s *ProofTreeStore) GetRankValue(cidNumber link.CidNumber) (float64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.tree == nil {
+		return 0, ErrProofsNotAvailable
+	}
+	if int(cidNumber) >= s.storedValues {
+		return 0, nil
+	}
+
+	valueBytes := make([]byte, 8)
+	if _, err := s.valuesFile.ReadAt(valueBytes, int64(cidNumber)*8); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(valueBytes)), nil
+}
+
+func (s *ProofTreeStore) GetIndexProofs(cidNumber link.CidNumber) ([]merkle.Proof, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.tree == nil {
+		return nil, ErrProofsNotAvailable
+	}
+	return s.tree.GetIndexProofs(int(cidNumber))
+}
+
+func (s *ProofTreeStore) GetMultiProof(indices []int) (*merkle.MultiProof, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.tree == nil {
+		return nil, ErrProofsNotAvailable
+	}
+	return s.tree.GetMultiProof(indices)
+}
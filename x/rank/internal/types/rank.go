@@ -20,14 +20,14 @@ type Rank struct {
 	TopCIDs	   []RankedCidNumber
 }
 
-func NewRank(values []float64, logger log.Logger, fullTree bool) Rank {
+func NewRank(values []float64, logger log.Logger, fullTree bool, treeVersion merkle.Version) Rank {
 	start := time.Now()
-	merkleTree := merkle.NewTree(sha256.New(), fullTree)
-	for _, f64 := range values {
-		rankBytes := make([]byte, 8)
-		binary.LittleEndian.PutUint64(rankBytes, math.Float64bits(f64))
-		merkleTree.Push(rankBytes)
+	ranksBytes := make([][]byte, len(values))
+	for i, f64 := range values {
+		ranksBytes[i] = make([]byte, 8)
+		binary.LittleEndian.PutUint64(ranksBytes[i], math.Float64bits(f64))
 	}
+	merkleTree := merkle.NewTreeInParallel(sha256.New, fullTree, treeVersion, ranksBytes)
 	logger.Info("Rank constructing tree", "time", time.Since(start))
 
 	// NOTE fulltree true if search index enabled
@@ -39,10 +39,50 @@ func NewRank(values []float64, logger log.Logger, fullTree bool) Rank {
 	return Rank{Values: values, MerkleTree: merkleTree, CidCount: uint64(len(values)), TopCIDs: newSortedCIDs}
 }
 
-func NewFromMerkle(cidCount uint64, treeBytes []byte) Rank {
+// Builds rank reusing merkle tree of previous rank, only elements of changed values are rehashed.
+// Previous rank tree is modified in place, so previous rank should not be used after.
+// Falls back to building new tree if previous tree could not be reused.
+func NewRankReusingTree(
+	values []float64, previous Rank, logger log.Logger, fullTree bool, treeVersion merkle.Version,
+) Rank {
+
+	previousTree := previous.MerkleTree
+	if !fullTree || previousTree == nil || !previousTree.IsFull() || previousTree.Version() != treeVersion ||
+		previous.Values == nil || len(previous.Values) > len(values) ||
+		previousTree.LeavesCount() != len(previous.Values) {
+		return NewRank(values, logger, fullTree, treeVersion)
+	}
+
+	start := time.Now()
+	changedIndices := make([]int, 0)
+	changedElements := make([][]byte, 0)
+	for i, f64 := range previous.Values {
+		if math.Float64bits(f64) != math.Float64bits(values[i]) {
+			rankBytes := make([]byte, 8)
+			binary.LittleEndian.PutUint64(rankBytes, math.Float64bits(values[i]))
+			changedIndices = append(changedIndices, i)
+			changedElements = append(changedElements, rankBytes)
+		}
+	}
+	previousTree.UpdateBulk(changedIndices, changedElements)
+
+	for _, f64 := range values[len(previous.Values):] {
+		rankBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(rankBytes, math.Float64bits(f64))
+		previousTree.Push(rankBytes)
+	}
+	logger.Info(
+		"Rank updating tree", "time", time.Since(start), "changed", len(changedIndices),
+		"added", len(values)-len(previous.Values),
+	)
+
+	return Rank{Values: values, MerkleTree: previousTree, CidCount: uint64(len(values)), TopCIDs: BuildTop(values, 1000)}
+}
+
+func NewFromMerkle(cidCount uint64, treeBytes []byte, treeVersion merkle.Version) Rank {
 	rank := Rank{
 		Values:     nil,
-		MerkleTree: merkle.NewTree(sha256.New(), false),
+		MerkleTree: merkle.NewTree(sha256.New(), false, treeVersion),
 		CidCount:   cidCount,
 		TopCIDs:    nil,
 	}
@@ -109,3 +149,20 @@ func BuildTop(values []float64, size int) []RankedCidNumber {
 	}
 	return newSortedCIDs
 }
+
+// Returns at most k cids with the highest non zero ranks.
+// Unlike BuildTop used for network rank top, it's exact for values with less than k non zero ranks.
+func BuildTopK(values []float64, k int) []RankedCidNumber {
+	sortedCIDs := make(sortableCidNumbers, 0)
+	for cid, rank := range values {
+		if rank == 0 {
+			continue
+		}
+		sortedCIDs = append(sortedCIDs, RankedCidNumber{link.CidNumber(cid), rank})
+	}
+	sort.Stable(sort.Reverse(sortedCIDs))
+	if len(sortedCIDs) > k {
+		sortedCIDs = sortedCIDs[0:k]
+	}
+	return sortedCIDs
+}
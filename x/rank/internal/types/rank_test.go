@@ -1 +1,71 @@
 package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cybercongress/go-cyber/merkle"
+)
+
+func testRankValues(count int, seed float64) []float64 {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = seed / float64(i+1)
+	}
+	return values
+}
+
+func TestNewRankReusingTreeEqualsNewRank(t *testing.T) {
+	logger := log.NewNopLogger()
+
+	for _, version := range []merkle.Version{merkle.LegacyVersion, merkle.DomainSeparatedVersion} {
+		previous := NewRank(testRankValues(37, 1), logger, true, version)
+
+		// some values are changed, some are kept and new cids are added
+		values := testRankValues(53, 1)
+		for i := 0; i < len(values); i += 3 {
+			values[i] = 0.5 / float64(i+1)
+		}
+		expected := NewRank(values, logger, true, version)
+
+		rank := NewRankReusingTree(values, previous, logger, true, version)
+		require.Equal(t, expected.MerkleTree.RootHash(), rank.MerkleTree.RootHash())
+		require.Equal(t, expected.MerkleTree.LeavesCount(), rank.MerkleTree.LeavesCount())
+		require.Equal(t, expected.CidCount, rank.CidCount)
+		require.Equal(t, expected.TopCIDs, rank.TopCIDs)
+		for i := range values {
+			require.Equal(t, expected.MerkleTree.GetIndexProofs(i), rank.MerkleTree.GetIndexProofs(i))
+		}
+	}
+}
+
+func TestNewRankReusingTreeFallsBackToNewRank(t *testing.T) {
+	logger := log.NewNopLogger()
+	values := testRankValues(20, 1)
+	expected := NewRank(values, logger, true, merkle.DomainSeparatedVersion)
+
+	previousRanks := map[string]Rank{
+		"more cids":     NewRank(testRankValues(21, 2), logger, true, merkle.DomainSeparatedVersion),
+		"other version": NewRank(testRankValues(10, 2), logger, true, merkle.LegacyVersion),
+		"not full tree": NewRank(testRankValues(10, 2), logger, false, merkle.DomainSeparatedVersion),
+		"empty":         {},
+	}
+	for name, previous := range previousRanks {
+		previousTree := previous.MerkleTree
+		rank := NewRankReusingTree(values, previous, logger, true, merkle.DomainSeparatedVersion)
+		require.Equal(t, expected.MerkleTree.RootHash(), rank.MerkleTree.RootHash(), name)
+		require.True(t, rank.MerkleTree.IsFull(), name)
+		require.True(t, rank.MerkleTree != previousTree, name)
+	}
+}
+
+func TestBuildTopK(t *testing.T) {
+	values := []float64{0, 0.3, 0.5, 0.2, 0}
+
+	require.Equal(t, []RankedCidNumber{{2, 0.5}, {1, 0.3}}, BuildTopK(values, 2))
+	// zero ranks are skipped, so there could be less than k cids
+	require.Equal(t, []RankedCidNumber{{2, 0.5}, {1, 0.3}, {3, 0.2}}, BuildTopK(values, 4))
+	require.Empty(t, BuildTopK(values, 0))
+}
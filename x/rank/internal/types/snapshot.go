@@ -0,0 +1,29 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/cybercongress/go-cyber/merkle"
+)
+
+var ErrCidNotRanked = errors.New("no such cid found")
+
+// Kind of proofs taken with network rank snapshot
+type SnapshotProofs int
+
+const (
+	WithoutProofs   SnapshotProofs = iota
+	WithIndexProofs                // proofs of single cid
+	WithMultiProof                 // multiproof of all requested cids
+)
+
+// Network rank values of cids with their proofs, all taken from the same network rank tree.
+// Tree is produced at the end of block of given height, so its root hash is app hash of next block header.
+type RankSnapshot struct {
+	Values      []float64
+	Proofs      []merkle.Proof
+	MultiProof  *merkle.MultiProof
+	LeavesCount int
+	TreeVersion merkle.Version
+	Height      int64
+}